@@ -1,13 +1,16 @@
 package rev
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -41,15 +44,43 @@ func ValueBinder(f func(value string, typ reflect.Type) reflect.Value) Binder {
 
 // These are the lookups to find a Binder for any type of data.
 // The most specific binder found will be used (Type before Kind)
+//
+// The maps may be changed directly only during initialization (e.g. in an
+// init function).  Use RegisterBinder to add a binder once requests are being
+// served.
 var (
 	TypeBinders = make(map[reflect.Type]Binder)
 	KindBinders = make(map[reflect.Kind]Binder)
+
+	// The conversion checks used by BindResult for the built-in TypeBinders
+	// that can fail.  RegisterBinder removes the check for a type whose binder
+	// it replaces, so an override should be registered with it.
+	builtinChecks = make(map[reflect.Type]func(val string) error)
+
+	// Guards TypeBinders, KindBinders, and builtinChecks.
+	bindersLock sync.RWMutex
 )
 
+// Register a function that binds the given type from all of the raw values
+// for a parameter.  It takes precedence over the built-in Binders, so it may
+// be used both for application types and to override the default behavior.
+// Binders are intended to be registered during initialization, but it is safe
+// to register them while requests are being bound.
+func RegisterBinder(typ reflect.Type, f func(values []string) reflect.Value) {
+	bindersLock.Lock()
+	defer bindersLock.Unlock()
+	delete(builtinChecks, typ)
+	TypeBinders[typ] = func(params *Params, name string, typ reflect.Type) reflect.Value {
+		return f(params.Values[name])
+	}
+}
+
 // Sadly, the binder lookups can not be declared initialized -- that results in
 // an "initialization loop" compile error.
 func init() {
 	intBinder := ValueBinder(bindInt)
+	uintBinder := ValueBinder(bindUint)
+	floatBinder := ValueBinder(bindFloat)
 
 	KindBinders[reflect.Int] = intBinder
 	KindBinders[reflect.Int8] = intBinder
@@ -57,16 +88,28 @@ func init() {
 	KindBinders[reflect.Int32] = intBinder
 	KindBinders[reflect.Int64] = intBinder
 
+	KindBinders[reflect.Uint] = uintBinder
+	KindBinders[reflect.Uint8] = uintBinder
+	KindBinders[reflect.Uint16] = uintBinder
+	KindBinders[reflect.Uint32] = uintBinder
+	KindBinders[reflect.Uint64] = uintBinder
+
+	KindBinders[reflect.Float32] = floatBinder
+	KindBinders[reflect.Float64] = floatBinder
+
 	KindBinders[reflect.String] = ValueBinder(bindStr)
 	KindBinders[reflect.Bool] = ValueBinder(bindBool)
 	KindBinders[reflect.Slice] = bindSlice
 	KindBinders[reflect.Struct] = bindStruct
 	KindBinders[reflect.Ptr] = bindPointer
 
-	TypeBinders[reflect.TypeOf(time.Time{})] = ValueBinder(bindTime)
+	timeType := reflect.TypeOf(time.Time{})
+	TypeBinders[timeType] = ValueBinder(bindTime)
+	builtinChecks[timeType] = checkTime
 
 	// Uploads
 	TypeBinders[reflect.TypeOf(&os.File{})] = bindFile
+	TypeBinders[reflect.TypeOf(&multipart.FileHeader{})] = bindFileHeader
 	TypeBinders[reflect.TypeOf([]byte{})] = bindByteArray
 	TypeBinders[reflect.TypeOf((*io.Reader)(nil)).Elem()] = bindReadSeeker
 	TypeBinders[reflect.TypeOf((*io.ReadSeeker)(nil)).Elem()] = bindReadSeeker
@@ -74,34 +117,92 @@ func init() {
 
 var (
 	// Applications can add custom time formats to this array, and they will be
-	// automatically attempted when binding a time.Time.
-	TimeFormats = []string{"2006-01-02", "2006-01-02 15:04"}
+	// automatically attempted (in order) when binding a time.Time.
+	// A value that matches none of them binds to the zero Time.
+	TimeFormats = []string{"2006-01-02", "2006-01-02 15:04", time.RFC3339}
+
+	// If set (e.g. to ","), a single value for a slice under its bare name is
+	// split on this separator, so that ids=1,2,3 binds to []int{1, 2, 3}.  By
+	// default, such a value binds as one element.
+	SliceSeparator = ""
 )
 
+// The string and bool binders convert to the target type, so that named types
+// (e.g. type Status string) are bound as themselves.
 func bindStr(val string, typ reflect.Type) reflect.Value {
-	return reflect.ValueOf(val)
+	return reflect.ValueOf(val).Convert(typ)
 }
 
+// The numeric binders honor the size of the target type: values that do not
+// fit (e.g. "300" for an int8) are treated as invalid, and bind to 0.
 func bindInt(val string, typ reflect.Type) reflect.Value {
-	intValue, err := strconv.Atoi(val)
+	intValue, err := strconv.ParseInt(val, 10, typ.Bits())
 	if err != nil {
 		WARN.Println("BindInt:", err)
+		return reflect.Zero(typ)
 	}
-	return reflect.ValueOf(intValue)
+	pValue := reflect.New(typ)
+	pValue.Elem().SetInt(intValue)
+	return pValue.Elem()
 }
 
-// Booleans support a couple different value formats:
-// "true" and "false"
-// "on" and "" (a checkbox)
+func bindUint(val string, typ reflect.Type) reflect.Value {
+	uintValue, err := strconv.ParseUint(val, 10, typ.Bits())
+	if err != nil {
+		WARN.Println("BindUint:", err)
+		return reflect.Zero(typ)
+	}
+	pValue := reflect.New(typ)
+	pValue.Elem().SetUint(uintValue)
+	return pValue.Elem()
+}
+
+func bindFloat(val string, typ reflect.Type) reflect.Value {
+	floatValue, err := strconv.ParseFloat(val, typ.Bits())
+	if err != nil {
+		WARN.Println("BindFloat:", err)
+		return reflect.Zero(typ)
+	}
+	pValue := reflect.New(typ)
+	pValue.Elem().SetFloat(floatValue)
+	return pValue.Elem()
+}
+
+// The spellings of booleans (in lower case) understood by the bool binder and
+// Params.GetBool:
+// "true" and "false" (or "t" and "f")
+// "on" and "off" (a checkbox)
+// "yes" and "no"
 // "1" and "0" (why not)
+var boolValues = map[string]bool{
+	"true": true, "t": true, "on": true, "yes": true, "1": true,
+	"false": false, "f": false, "off": false, "no": false, "0": false,
+}
+
+// Parses any of the boolValues, ignoring case and surrounding space.  The
+// second return value is false if val is not one of them.
+func parseBool(val string) (bool, bool) {
+	b, ok := boolValues[strings.ToLower(strings.TrimSpace(val))]
+	return b, ok
+}
+
+// Booleans are bound from any of the boolValues.  Any other value (including
+// "", as sent for an unchecked checkbox) binds to false.
 func bindBool(val string, typ reflect.Type) reflect.Value {
-	v := strings.TrimSpace(strings.ToLower(val))
-	switch v {
-	case "true", "on", "1":
-		return reflect.ValueOf(true)
+	b, _ := parseBool(val)
+	return reflect.ValueOf(b).Convert(typ)
+}
+
+// Split a single slice value on SliceSeparator, skipping empty parts, so that
+// "1,,2" binds two elements and "" binds none.
+func splitValue(val string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(val, SliceSeparator) {
+		if part != "" {
+			parts = append(parts, part)
+		}
 	}
-	// Return false by default.
-	return reflect.ValueOf(false)
+	return parts
 }
 
 // Used to keep track of the index for individual keyvalues.
@@ -114,6 +215,15 @@ type sliceValue struct {
 // elements, and then sets them to their appropriate location in the slice.
 // If elements are provided without an explicit index, they are added (in
 // unspecified order) to the end of the slice.
+//
+// A field repeated under the bare name (e.g. tag=a&tag=b, as submitted by a
+// group of checkboxes) is also bound as un-indexed elements.  Elements that
+// fail to convert are bound to their zero value, rather than being skipped.
+//
+// If SliceSeparator is set, a single value under the bare name is split on it
+// (e.g. ids=1,2,3), skipping empty parts.  The separator can not be escaped:
+// to bind an element containing it, index it (e.g. ids[0]=a,b) or repeat the
+// field.
 func bindSlice(params *Params, name string, typ reflect.Type) reflect.Value {
 	// Collect an array of slice elements with their indexes (and the max index).
 	maxIndex := -1
@@ -122,31 +232,36 @@ func bindSlice(params *Params, name string, typ reflect.Type) reflect.Value {
 
 	// Factor out the common slice logic (between form values and files).
 	processElement := func(key string, vals []string, files []*multipart.FileHeader) {
-		if !strings.HasPrefix(key, name+"[") {
-			return
-		}
-
-		// Extract the index, and the index where a sub-key starts. (e.g. field[0].subkey)
-		index := -1
-		leftBracket, rightBracket := len(name), strings.Index(key[len(name):], "]")+len(name)
-		if rightBracket > leftBracket+1 {
-			index, _ = strconv.Atoi(key[leftBracket+1 : rightBracket])
-		}
-		subKeyIndex := rightBracket + 1
+		if key != name {
+			if !strings.HasPrefix(key, name+"[") {
+				return
+			}
 
-		// Handle the indexed case.
-		if index > -1 {
-			if index > maxIndex {
-				maxIndex = index
+			// Extract the index, and the index where a sub-key starts. (e.g. field[0].subkey)
+			index := -1
+			leftBracket, rightBracket := len(name), strings.Index(key[len(name):], "]")+len(name)
+			if rightBracket > leftBracket+1 {
+				index, _ = strconv.Atoi(key[leftBracket+1 : rightBracket])
+			}
+			subKeyIndex := rightBracket + 1
+
+			// Handle the indexed case.
+			if index > -1 {
+				if index > maxIndex {
+					maxIndex = index
+				}
+				sliceValues = append(sliceValues, sliceValue{
+					index: index,
+					value: Bind(params, key[:subKeyIndex], typ.Elem()),
+				})
+				return
 			}
-			sliceValues = append(sliceValues, sliceValue{
-				index: index,
-				value: Bind(params, key[:subKeyIndex], typ.Elem()),
-			})
-			return
 		}
 
-		// It's an un-indexed element.  (e.g. element[])
+		// It's an un-indexed element.  (e.g. element[], or a repeated element)
+		if key == name && len(vals) == 1 && SliceSeparator != "" {
+			vals = splitValue(vals[0])
+		}
 		numNoIndex += len(vals) + len(files)
 		for _, val := range vals {
 			// Unindexed values can only be direct-bound.
@@ -227,8 +342,38 @@ func bindStruct(params *Params, name string, typ reflect.Type) reflect.Value {
 	return result
 }
 
+// Pointers are nil unless a (non-blank) value was provided, so that absent
+// fields can be told apart from fields that were set to the zero value.
 func bindPointer(params *Params, name string, typ reflect.Type) reflect.Value {
-	return Bind(params, name, typ.Elem()).Addr()
+	if !hasValue(params, name) {
+		return reflect.Zero(typ)
+	}
+	ptr := reflect.New(typ.Elem())
+	ptr.Elem().Set(Bind(params, name, typ.Elem()))
+	return ptr
+}
+
+// Returns true if a non-blank value or a file was provided for the name, or
+// for any of its sub-keys (e.g. name.field or name[0]).
+func hasValue(params *Params, name string) bool {
+	isKey := func(key string) bool {
+		return key == name || strings.HasPrefix(key, name+".") || strings.HasPrefix(key, name+"[")
+	}
+	for key, vals := range params.Values {
+		if isKey(key) {
+			for _, val := range vals {
+				if val != "" {
+					return true
+				}
+			}
+		}
+	}
+	for key, fileHeaders := range params.Files {
+		if isKey(key) && len(fileHeaders) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // This expects a single keyValue.
@@ -241,6 +386,13 @@ func bindTime(val string, typ reflect.Type) reflect.Value {
 	return reflect.Zero(typ)
 }
 
+func checkTime(val string) error {
+	if bindTime(val, reflect.TypeOf(time.Time{})).Interface().(time.Time).IsZero() {
+		return fmt.Errorf("%q does not match any of the TimeFormats", val)
+	}
+	return nil
+}
+
 // Helper that returns an upload of the given name, or nil.
 func getMultipartFile(params *Params, name string) multipart.File {
 	for _, fileHeader := range params.Files[name] {
@@ -289,6 +441,13 @@ func bindFile(params *Params, name string, typ reflect.Type) reflect.Value {
 	return reflect.ValueOf(tmpFile)
 }
 
+func bindFileHeader(params *Params, name string, typ reflect.Type) reflect.Value {
+	if fileHeaders := params.Files[name]; len(fileHeaders) > 0 {
+		return reflect.ValueOf(fileHeaders[0])
+	}
+	return reflect.Zero(typ)
+}
+
 func bindByteArray(params *Params, name string, typ reflect.Type) reflect.Value {
 	if reader := getMultipartFile(params, name); reader != nil {
 		b, err := ioutil.ReadAll(reader)
@@ -321,17 +480,73 @@ func Bind(params *Params, name string, typ reflect.Type) reflect.Value {
 		return reflect.ValueOf(nil)
 	}
 
+	// The lock is not held while binding, since binders may Bind recursively.
+	bindersLock.RLock()
 	binder, ok := TypeBinders[typ]
 	if !ok {
 		binder, ok = KindBinders[typ.Kind()]
-		if !ok {
-			WARN.Println("No binder for type:", typ)
-			return reflect.Zero(typ)
-		}
+	}
+	bindersLock.RUnlock()
+	if !ok {
+		WARN.Println("No binder for type:", typ)
+		return reflect.Zero(typ)
 	}
 	return binder(params, name, typ)
 }
 
+// BindResult is like Bind, but also returns an error if the parameter is
+// present and its value can not be converted to the type (e.g. "abc" for an
+// int), so that an invalid value may be told apart from a valid zero.
+// A missing parameter is not an error.
+//
+// Only numeric, bool, and time.Time values (and pointers to them) are checked;
+// for other types, and for types with a registered TypeBinder, the error is
+// always nil.
+func BindResult(params *Params, name string, typ reflect.Type) (reflect.Value, error) {
+	val := Bind(params, name, typ)
+	vals := params.Values[name]
+	if typ == nil || len(vals) == 0 {
+		return val, nil
+	}
+	return val, conversionError(vals[0], typ)
+}
+
+// Returns the error (if any) that the built-in binder for typ encounters
+// converting the given value.  Registered binders are trusted not to fail.
+func conversionError(val string, typ reflect.Type) error {
+	bindersLock.RLock()
+	check, builtin := builtinChecks[typ]
+	_, custom := TypeBinders[typ]
+	bindersLock.RUnlock()
+	if builtin {
+		return check(val)
+	}
+	if custom {
+		return nil
+	}
+
+	var err error
+	switch typ.Kind() {
+	case reflect.Ptr:
+		// A blank value binds to nil.
+		if val != "" {
+			err = conversionError(val, typ.Elem())
+		}
+	case reflect.Bool:
+		// A blank value is an unchecked checkbox.
+		if _, ok := parseBool(val); !ok && val != "" {
+			err = fmt.Errorf("%q is not a boolean", val)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(val, 10, typ.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(val, 10, typ.Bits())
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(val, typ.Bits())
+	}
+	return err
+}
+
 func BindValue(val string, typ reflect.Type) reflect.Value {
 	return Bind(&Params{Values: map[string][]string{"": {val}}}, "", typ)
 }
@@ -339,3 +554,131 @@ func BindValue(val string, typ reflect.Type) reflect.Value {
 func BindFile(fileHeader *multipart.FileHeader, typ reflect.Type) reflect.Value {
 	return Bind(&Params{Files: map[string][]*multipart.FileHeader{"": {fileHeader}}}, "", typ)
 }
+
+// Bind the exported fields of the struct that dst points to, each from the
+// parameter named by its "param" tag, or else by the field name.  For example:
+//
+//   type Signup struct {
+//   	Email    string `param:"email"`
+//   	Age      int    `param:"age"`
+//   	Internal string `param:"-"` // never bound
+//   }
+//
+// Fields are bound with Bind, so any bindable type may be used.  Fields whose
+// parameter is absent are left unchanged, so dst may hold defaults.  The
+// error reports the first value that could not be converted (as BindResult),
+// though the remaining fields are still bound; it is also an error if dst is
+// not a non-nil pointer to a struct.
+func BindStruct(params *Params, dst interface{}) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindStruct expects a pointer to a struct, got %T", dst)
+	}
+
+	var firstErr error
+	val := ptr.Elem()
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("param")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := params.Values[name]; !ok && !hasValue(params, name) {
+			continue
+		}
+
+		boundVal, err := BindResult(params, name, field.Type)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("BindStruct: %s: %s", name, err)
+		}
+		val.Field(i).Set(boundVal)
+	}
+	return firstErr
+}
+
+// Unbind is the inverse of Bind: it adds the string representation of val to
+// the values under the given name, in a form that Bind will accept.  Slices
+// are added as repeated values (a lone element that Bind would split on
+// SliceSeparator, or drop for being empty, is added as name[0] instead), and
+// struct fields as name.Field.  Times are
+// formatted with the first of TimeFormats that preserves them.
+func Unbind(values url.Values, name string, val interface{}) {
+	unbindValue(values, name, reflect.ValueOf(val))
+}
+
+// Unbind the only element of a slice, indexing it if it would not bind back
+// as a single element under the bare name.
+func unbindLoneElement(values url.Values, name string, elem reflect.Value) {
+	unbound := make(url.Values)
+	unbindValue(unbound, name, elem)
+	if vals := unbound[name]; len(vals) == 1 &&
+		(vals[0] == "" || SliceSeparator != "" && strings.Contains(vals[0], SliceSeparator)) {
+		unbound[name+"[0]"] = vals
+		delete(unbound, name)
+	}
+	for key, vals := range unbound {
+		values[key] = append(values[key], vals...)
+	}
+}
+
+func unbindValue(values url.Values, name string, val reflect.Value) {
+	if !val.IsValid() {
+		return
+	}
+
+	if t, ok := val.Interface().(time.Time); ok {
+		values.Add(name, formatTime(t))
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !val.IsNil() {
+			unbindValue(values, name, val.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		if val.Len() == 1 {
+			unbindLoneElement(values, name, val.Index(0))
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			unbindValue(values, name, val.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if field := val.Type().Field(i); field.PkgPath == "" {
+				unbindValue(values, name+"."+field.Name, val.Field(i))
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		values.Add(name, strconv.FormatInt(val.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		values.Add(name, strconv.FormatUint(val.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		values.Add(name, strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits()))
+	case reflect.Bool:
+		values.Add(name, strconv.FormatBool(val.Bool()))
+	case reflect.String:
+		values.Add(name, val.String())
+	default:
+		WARN.Println("No unbinder for type:", val.Type())
+	}
+}
+
+// Format the time with the first of TimeFormats that loses no information.
+func formatTime(t time.Time) string {
+	for _, f := range TimeFormats {
+		str := t.Format(f)
+		if r, err := time.Parse(f, str); err == nil && r.Equal(t) {
+			return str
+		}
+	}
+	return t.Format(time.RFC3339Nano)
+}
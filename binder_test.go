@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
@@ -26,19 +28,38 @@ var (
 	PARAMS = map[string][]string{
 		"int":             {"1"},
 		"str":             {"hello"},
+		"int8":            {"-100"},
+		"int64":           {"-9223372036854775808"},
+		"uint":            {"7"},
+		"uint64":          {"18446744073709551615"},
+		"float32":         {"1.5"},
+		"float64":         {"-0.125"},
+		"int8Overflow":    {"300"},
+		"uint8Negative":   {"-1"},
 		"bool-true":       {"true"},
 		"bool-1":          {"1"},
 		"bool-on":         {"on"},
 		"bool-false":      {"false"},
 		"bool-0":          {"0"},
 		"bool-off":        {""},
+		"bool-yes":        {"yes"},
+		"bool-TRUE":       {"TRUE"},
+		"bool-On":         {"On"},
+		"bool-no":         {"no"},
+		"bool-OFF":        {"OFF"},
+		"bool-FALSE":      {"FALSE"},
 		"date":            {"1982-07-09"},
 		"datetime":        {"1982-07-09 21:30"},
 		"customDate":      {"07/09/1982"},
+		"rfc3339":         {"1982-07-09T21:30:00Z"},
+		"invalidDate":     {"07-09-1982"},
 		"arr[0]":          {"1"},
 		"arr[1]":          {"2"},
 		"arr[3]":          {"3"},
 		"uarr[]":          {"1", "2"},
+		"tag":             {"a", "b", "c"},
+		"tagInt":          {"1", "x", "3"},
+		"tagOne":          {"a"},
 		"arruarr[0][]":    {"1", "2"},
 		"arruarr[1][]":    {"3", "4"},
 		"2darr[0][0]":     {"0"},
@@ -72,17 +93,33 @@ var (
 var binderTestCases = map[string]interface{}{
 	"int":        1,
 	"str":        "hello",
+	"int8":       int8(-100),
+	"int64":      int64(-9223372036854775808),
+	"uint":       uint(7),
+	"uint64":     uint64(18446744073709551615),
+	"float32":    float32(1.5),
+	"float64":    -0.125,
 	"bool-true":  true,
 	"bool-1":     true,
 	"bool-on":    true,
 	"bool-false": false,
 	"bool-0":     false,
 	"bool-off":   false,
+	"bool-yes":   true,
+	"bool-TRUE":  true,
+	"bool-On":    true,
+	"bool-no":    false,
+	"bool-FALSE": false,
+	"bool-OFF":   false,
 	"date":       testDate,
 	"datetime":   testDatetime,
 	"customDate": testDate,
+	"rfc3339":    testDatetime,
 	"arr":        []int{1, 2, 0, 3},
 	"uarr":       []int{1, 2},
+	"tag":        []string{"a", "b", "c"},
+	"tagInt":     []int{1, 0, 3},
+	"tagOne":     []string{"a"},
 	"arruarr":    [][]int{{1, 2}, {3, 4}},
 	"2darr":      [][]int{{0, 1}, {10, 11}},
 	"A":          A{Id: 123, Name: "rob"},
@@ -104,11 +141,14 @@ var binderTestCases = map[string]interface{}{
 
 	// Invalid value tests (the result should always be the zero value for that type)
 	// The point of these is to ensure that invalid user input does not cause panics.
-	"invalidInt":  0,
-	"invalidInt2": 0,
-	"invalidBool": false,
-	"invalidArr":  []int{},
-	"priv":        A{},
+	"invalidInt":    0,
+	"invalidInt2":   0,
+	"int8Overflow":  int8(0),
+	"uint8Negative": uint8(0),
+	"invalidBool":   false,
+	"invalidArr":    []int{0}, // A repeated field binds each element; this one fails to convert.
+	"invalidDate":   time.Time{},
+	"priv":          A{},
 }
 
 func init() {
@@ -123,6 +163,17 @@ var fileBindings = []struct{ val, arrval, f interface{} }{
 	{(*[]byte)(nil), [][]byte{}, func(b []byte) []byte { return b }},
 	{(*io.Reader)(nil), []io.Reader{}, ioutil.ReadAll},
 	{(*io.ReadSeeker)(nil), []io.ReadSeeker{}, ioutil.ReadAll},
+	{(**multipart.FileHeader)(nil), []*multipart.FileHeader{}, readFileHeader},
+}
+
+func readFileHeader(fileHeader *multipart.FileHeader) []byte {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	content, _ := ioutil.ReadAll(file)
+	return content
 }
 
 func TestBinder(t *testing.T) {
@@ -191,6 +242,258 @@ func TestBinder(t *testing.T) {
 	}
 }
 
+func TestBindSeparatedSlice(t *testing.T) {
+	params := &Params{Values: map[string][]string{
+		"ids":      {"1,2,3"},
+		"names":    {"a,b"},
+		"one":      {"x"},
+		"repeated": {"a,b", "c"},
+		"idx[0]":   {"a,b"},
+		"empty":    {""},
+		"gaps":     {"1,,2,"},
+	}}
+
+	// Splitting is off by default.
+	valEq(t, "names (default)", Bind(params, "names", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a,b"}))
+
+	defer func(sep string) { SliceSeparator = sep }(SliceSeparator)
+	SliceSeparator = ","
+	valEq(t, "ids", Bind(params, "ids", reflect.TypeOf([]int{})), reflect.ValueOf([]int{1, 2, 3}))
+	valEq(t, "names", Bind(params, "names", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a", "b"}))
+	valEq(t, "one", Bind(params, "one", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"x"}))
+	valEq(t, "repeated", Bind(params, "repeated", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a,b", "c"}))
+	valEq(t, "indexed", Bind(params, "idx", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a,b"}))
+	valEq(t, "empty", Bind(params, "empty", reflect.TypeOf([]int{})), reflect.ValueOf([]int{}))
+	valEq(t, "gaps", Bind(params, "gaps", reflect.TypeOf([]int{})), reflect.ValueOf([]int{1, 2}))
+
+	// A lone element containing the separator is unbound so as to survive it.
+	values := make(url.Values)
+	Unbind(values, "city", []string{"New York, NY"})
+	valEq(t, "city (round trip)", Bind(&Params{Values: values}, "city", reflect.TypeOf([]string{})),
+		reflect.ValueOf([]string{"New York, NY"}))
+
+	SliceSeparator = ";"
+	valEq(t, "ids (;)", Bind(params, "ids", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"1,2,3"}))
+	SliceSeparator = ""
+	valEq(t, "names (disabled)", Bind(params, "names", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a,b"}))
+}
+
+func TestBindPointer(t *testing.T) {
+	params := &Params{Values: map[string][]string{
+		"age":   {"42"},
+		"zero":  {"0"},
+		"blank": {""},
+	}}
+	intPtrType := reflect.TypeOf((*int)(nil))
+
+	for _, name := range []string{"missing", "blank"} {
+		if actual := Bind(params, name, intPtrType); !actual.IsNil() {
+			t.Errorf("%s: expected a nil pointer, got %v", name, actual.Elem())
+		}
+	}
+
+	for name, expected := range map[string]int{"age": 42, "zero": 0} {
+		actual := Bind(params, name, intPtrType)
+		if actual.IsNil() {
+			t.Errorf("%s: expected a pointer, got nil", name)
+			continue
+		}
+		eq(t, name, actual.Elem().Interface(), expected)
+	}
+}
+
+func TestUnbind(t *testing.T) {
+	tests := map[string]interface{}{
+		"int":      123,
+		"negative": int64(-5),
+		"float":    float32(1.5),
+		"uint":     uint16(8),
+		"bool":     true,
+		"str":      "hello world",
+		"date":     testDate,
+		"datetime": testDatetime,
+		"slice":    []string{"a", "b"},
+		"intSlice": []int{1, 2, 3},
+		"oneSlice": []string{"x"},
+		"sepSlice": []string{"a,b"},
+		"blanks":   []string{""},
+		"struct":   A{Id: 123, Name: "rob", B: B{Extra: "hello"}},
+		"ptr":      &A{Id: 5},
+	}
+
+	values := make(url.Values)
+	for name, val := range tests {
+		Unbind(values, name, val)
+	}
+
+	eq(t, "int (unbound)", values.Get("int"), "123")
+	eq(t, "date (unbound)", values.Get("date"), "1982-07-09")
+	eq(t, "datetime (unbound)", values.Get("datetime"), "1982-07-09 21:30")
+	eq(t, "struct (unbound)", values.Get("struct.B.Extra"), "hello")
+	eq(t, "oneSlice (unbound)", values.Get("oneSlice"), "x")
+	eq(t, "sepSlice (unbound)", values.Get("sepSlice"), "a,b")
+
+	// Bind them back, and compare to the original.
+	params := &Params{Values: values}
+	for name, val := range tests {
+		valEq(t, name, Bind(params, name, reflect.TypeOf(val)), reflect.ValueOf(val))
+	}
+}
+
+func TestBindResult(t *testing.T) {
+	params := &Params{Values: map[string][]string{
+		"zero":  {"0"},
+		"abc":   {"abc"},
+		"big":   {"300"},
+		"date":  {"1982-07-09"},
+		"xdate": {"not a date"},
+		"maybe": {"maybe"},
+		"yes":   {"yes"},
+		"blank": {""},
+	}}
+
+	for _, test := range []struct {
+		name    string
+		typ     reflect.Type
+		invalid bool
+	}{
+		{"zero", reflect.TypeOf(0), false},
+		{"abc", reflect.TypeOf(0), true},
+		{"abc", reflect.TypeOf(uint(0)), true},
+		{"abc", reflect.TypeOf(0.0), true},
+		{"big", reflect.TypeOf(int8(0)), true},
+		{"big", reflect.TypeOf(int16(0)), false},
+		{"date", reflect.TypeOf(time.Time{}), false},
+		{"xdate", reflect.TypeOf(time.Time{}), true},
+		{"abc", reflect.TypeOf(""), false},
+		{"missing", reflect.TypeOf(0), false},
+		{"maybe", reflect.TypeOf(false), true},
+		{"yes", reflect.TypeOf(false), false},
+		{"blank", reflect.TypeOf(false), false},
+		{"abc", reflect.TypeOf((*int)(nil)), true},
+		{"zero", reflect.TypeOf((*int)(nil)), false},
+		{"blank", reflect.TypeOf((*int)(nil)), false},
+		{"xdate", reflect.TypeOf((**time.Time)(nil)), true},
+	} {
+		val, err := BindResult(params, test.name, test.typ)
+		eq(t, test.name+" as "+test.typ.String()+" (error)", err != nil, test.invalid)
+		if expected := Bind(params, test.name, test.typ); !reflect.DeepEqual(val.Interface(), expected.Interface()) {
+			t.Errorf("%s as %s: (expected) %v != %v (actual)", test.name, test.typ, expected, val)
+		}
+	}
+}
+
+func TestBindResultCustomTime(t *testing.T) {
+	timeType := reflect.TypeOf(time.Time{})
+	defer func(binder Binder, check func(string) error) {
+		TypeBinders[timeType], builtinChecks[timeType] = binder, check
+	}(TypeBinders[timeType], builtinChecks[timeType])
+	RegisterBinder(timeType, func(values []string) reflect.Value {
+		if len(values) > 0 && values[0] == "epoch" {
+			return reflect.ValueOf(time.Unix(0, 0))
+		}
+		return reflect.Zero(timeType)
+	})
+
+	params := &Params{Values: map[string][]string{"date": {"epoch"}}}
+	val, err := BindResult(params, "date", timeType)
+	eq(t, "custom time (error)", err, nil)
+	eq(t, "custom time", val.Interface().(time.Time).Equal(time.Unix(0, 0)), true)
+}
+
+type Status string
+
+const StatusActive Status = "active"
+
+type Flag bool
+
+func TestBindNamedTypes(t *testing.T) {
+	params := &Params{Values: map[string][]string{
+		"status": {"active"},
+		"flag":   {"on"},
+	}}
+
+	statusType := reflect.TypeOf(Status(""))
+	val := Bind(params, "status", statusType)
+	eq(t, "status type", val.Type(), statusType)
+	eq(t, "status", val.Interface(), StatusActive)
+	eq(t, "missing status type", Bind(params, "missing", statusType).Type(), statusType)
+
+	flagType := reflect.TypeOf(Flag(false))
+	val = Bind(params, "flag", flagType)
+	eq(t, "flag type", val.Type(), flagType)
+	eq(t, "flag", val.Interface(), Flag(true))
+}
+
+type signup struct {
+	Email    string `param:"email"`
+	Age      int    `param:"age"`
+	Agree    bool   `param:"agree"`
+	Name     string
+	Internal string `param:"-"`
+	Country  string `param:"country"`
+	secret   string
+}
+
+func TestBindStruct(t *testing.T) {
+	params := &Params{Values: map[string][]string{
+		"email":    {"rob@example.com"},
+		"age":      {"30"},
+		"agree":    {"on"},
+		"Name":     {"Rob"},
+		"Internal": {"x"},
+		"-":        {"x"},
+		"secret":   {"x"},
+	}}
+
+	dst := signup{Country: "US"}
+	if err := BindStruct(params, &dst); err != nil {
+		t.Fatal(err)
+	}
+	expected := signup{Email: "rob@example.com", Age: 30, Agree: true, Name: "Rob", Country: "US"}
+	if dst != expected {
+		t.Errorf("(expected) %#v != %#v (actual)", expected, dst)
+	}
+
+	params.Values["age"] = []string{"thirty"}
+	dst = signup{}
+	if err := BindStruct(params, &dst); err == nil {
+		t.Error("Expected an error for an invalid age")
+	}
+	eq(t, "bound despite invalid age", dst.Email, "rob@example.com")
+
+	if err := BindStruct(params, dst); err == nil {
+		t.Error("Expected an error for a non-pointer")
+	}
+
+	var optional struct {
+		Age *int `param:"age"`
+	}
+	if err := BindStruct(params, &optional); err == nil {
+		t.Error("Expected an error for an invalid *int")
+	}
+}
+
+type Money struct {
+	Cents int
+}
+
+func TestRegisterBinder(t *testing.T) {
+	moneyType := reflect.TypeOf(Money{})
+	RegisterBinder(moneyType, func(values []string) reflect.Value {
+		var dollars, cents int
+		if len(values) > 0 {
+			fmt.Sscanf(values[0], "%d.%d", &dollars, &cents)
+		}
+		return reflect.ValueOf(Money{dollars*100 + cents})
+	})
+	defer delete(TypeBinders, moneyType)
+
+	params := &Params{Values: map[string][]string{"price": {"12.34"}}}
+	valEq(t, "price", Bind(params, "price", moneyType), reflect.ValueOf(Money{1234}))
+	valEq(t, "missing", Bind(params, "missing", moneyType), reflect.ValueOf(Money{}))
+}
+
 // Helpers
 
 func valEq(t *testing.T, name string, actual, expected reflect.Value) {
@@ -1,8 +1,12 @@
 package rev
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -11,6 +15,7 @@ import (
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
 )
 
@@ -31,6 +36,13 @@ type Params struct {
 	url.Values
 	Files    map[string][]*multipart.FileHeader
 	tmpFiles []*os.File // Temp files used during the request.
+
+	// The values from each source, kept separately for the Query, Form, and
+	// Route accessors.
+	query, form, route url.Values
+
+	// The undecoded query string and urlencoded body values, for RawValue.
+	raw url.Values
 }
 
 // A signed cookie (and thus limited to 4kb in size).
@@ -188,22 +200,7 @@ func (c *Controller) Invoke(appControllerPtr reflect.Value, method reflect.Value
 	}()
 
 	// Clean up from the request.
-	defer func() {
-		// Delete temp files.
-		if c.Request.MultipartForm != nil {
-			err := c.Request.MultipartForm.RemoveAll()
-			if err != nil {
-				WARN.Println("Error removing temporary files:", err)
-			}
-		}
-
-		for _, tmpFile := range c.Params.tmpFiles {
-			err := os.Remove(tmpFile.Name())
-			if err != nil {
-				WARN.Println("Could not remove upload temp file:", err)
-			}
-		}
-	}()
+	defer cleanupRequest(c.Request.Request, c.Params)
 
 	// Run the plugins.
 	plugins.BeforeRequest(c)
@@ -567,48 +564,246 @@ func (f Flash) Success(msg string, args ...interface{}) {
 	}
 }
 
+// The maximum number of bytes of a multipart form that are held in memory.
+// The remainder of the uploaded files are stored in temporary files.
+var MaxMultipartMemory int64 = 32 << 20 // 32 MB
+
+// The maximum number of bytes read from an urlencoded or JSON request body.
+// Larger bodies are rejected, leaving no body parameters.
+var MaxBodySize int64 = 10 << 20 // 10 MB
+
+// Parse the parameters of the request from both the URL query string and the
+// body (a form, multipart form, or JSON object), for any method, so that GET
+// requests are bound the same way as POSTs.
+//
+// Where both provide a value for a key, the body's values come first in Values
+// (as with http.Request.FormValue): Get (and so Bind of a single value)
+// returns the body's value, so a query string parameter can not override a
+// form field, and a slice binds the body's values followed by the query
+// string's.  Use Query or Form to read from one source only.
 func ParseParams(req *Request) *Params {
-	var files map[string][]*multipart.FileHeader
+	var (
+		files map[string][]*multipart.FileHeader
+		form  = make(url.Values)
+	)
 
 	// Always want the url parameters.
-	values := req.URL.Query()
+	query := req.URL.Query()
+	raw := make(url.Values)
 
 	// Parse the body depending on the content type.
 	switch req.ContentType {
 	case "application/x-www-form-urlencoded":
-		// Typical form.
+		// Typical form.  The body is read up front, to keep its raw values.
+		if hasBody(req.Request) {
+			body, err := ioutil.ReadAll(io.LimitReader(req.Body, MaxBodySize+1))
+			if err != nil {
+				WARN.Println("Error reading request body:", err)
+			} else if int64(len(body)) > MaxBodySize {
+				WARN.Println("Request body too large")
+				body = nil
+			}
+			rawValues(string(body), raw)
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
 		if err := req.ParseForm(); err != nil {
 			WARN.Println("Error parsing request body:", err)
 		} else {
-			for key, vals := range req.Form {
-				for _, val := range vals {
-					values.Add(key, val)
-				}
-			}
+			form = req.PostForm
 		}
 
 	case "multipart/form-data":
 		// Multipart form.
-		// TODO: Extract the multipart form param so app can set it.
-		if err := req.ParseMultipartForm(32 << 20 /* 32 MB */); err != nil {
+		if err := req.ParseMultipartForm(MaxMultipartMemory); err != nil {
 			WARN.Println("Error parsing request body:", err)
 		} else {
-			for key, vals := range req.MultipartForm.Value {
-				for _, val := range vals {
-					values.Add(key, val)
-				}
-			}
+			form = req.MultipartForm.Value
 			files = req.MultipartForm.File
 		}
+
+	case "application/json":
+		// JSON object.  Nested objects are flattened into dotted keys.
+		if !hasBody(req.Request) {
+			break
+		}
+		var obj map[string]interface{}
+		req.Body = http.MaxBytesReader(nil, req.Body, MaxBodySize)
+		decoder := json.NewDecoder(req.Body)
+		decoder.UseNumber()
+		if err := decoder.Decode(&obj); err != nil {
+			WARN.Println("Error parsing request body:", err)
+		} else {
+			flattenJson(form, "", obj)
+		}
+	}
+
+	// Merge the sources, body values first.
+	rawValues(req.URL.RawQuery, raw)
+	values := make(url.Values)
+	for _, source := range []url.Values{form, query} {
+		for key, vals := range source {
+			values[key] = append(values[key], vals...)
+		}
+	}
+
+	return &Params{Values: values, Files: files, query: query, form: form, raw: raw}
+}
+
+// Returns true if the request has a body that should be parsed: only the
+// methods whose body ParseForm would read (POST, PUT, and PATCH) are.
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && (req.Method == "POST" || req.Method == "PUT" || req.Method == "PATCH")
+}
+
+// WithParams adapts a function that takes the request's Params into an
+// http.Handler, for use outside of a controller.  The Params are parsed (as
+// for an action) before calling next, and any temp files from uploads are
+// removed after it returns.
+func WithParams(next func(*Params, http.ResponseWriter, *http.Request)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := ParseParams(NewRequest(r))
+		defer cleanupRequest(r, params)
+		next(params, w, r)
+	})
+}
+
+// Delete the temp files left by a request's multipart form and uploads.
+func cleanupRequest(req *http.Request, params *Params) {
+	if req.MultipartForm != nil {
+		err := req.MultipartForm.RemoveAll()
+		if err != nil {
+			WARN.Println("Error removing temporary files:", err)
+		}
 	}
 
-	return &Params{Values: values, Files: files}
+	for _, tmpFile := range params.tmpFiles {
+		err := os.Remove(tmpFile.Name())
+		if err != nil {
+			WARN.Println("Could not remove upload temp file:", err)
+		}
+	}
+}
+
+// Add the values from an urlencoded string (a query string or form body) to
+// raw, keyed by their decoded names but without decoding the values
+// themselves.  If raw is nil, a new url.Values is allocated.
+func rawValues(encoded string, raw url.Values) url.Values {
+	if raw == nil {
+		raw = make(url.Values)
+	}
+	for _, pair := range strings.Split(encoded, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			key, value = pair[:i], pair[i+1:]
+		}
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		raw[key] = append(raw[key], value)
+	}
+	return raw
+}
+
+// Add the decoded JSON value to the values, under the given key, using the
+// same key conventions as form fields.  For example:
+//   {"user": {"name": "rob", "tags": ["a", "b"], "posts": [{"id": 5}]}}
+// results in:
+//   user.name=rob, user.tags[0]=a, user.tags[1]=b, user.posts[0].id=5
+func flattenJson(values url.Values, key string, val interface{}) {
+	switch val := val.(type) {
+	case map[string]interface{}:
+		for k, v := range val {
+			if key != "" {
+				k = key + "." + k
+			}
+			flattenJson(values, k, v)
+		}
+	case []interface{}:
+		for i, v := range val {
+			flattenJson(values, fmt.Sprintf("%s[%d]", key, i), v)
+		}
+	case nil:
+		// A null is treated the same as a missing key.
+	default:
+		values.Add(key, fmt.Sprint(val))
+	}
 }
 
 func (p *Params) Bind(name string, typ reflect.Type) reflect.Value {
 	return Bind(p, name, typ)
 }
 
+// Add the path parameters from the route (e.g. {id} in "/users/{id}"), so that
+// they may be bound like any other parameter.  A path parameter replaces any
+// query or form values of the same name.
+func (p *Params) SetRoute(route map[string]string) {
+	if p.Values == nil {
+		p.Values = make(url.Values)
+	}
+	if p.route == nil {
+		p.route = make(url.Values)
+	}
+	for key, value := range route {
+		p.Values[key] = []string{value}
+		p.route.Set(key, value)
+	}
+}
+
+// These return the first value for the key from a single source, ignoring any
+// values of the same name from the others.  For example, use Form to accept a
+// value only from the request body, so that it can not be overridden by a
+// query string parameter.
+
+// Returns the first value from the URL query string, or "".
+func (p *Params) Query(key string) string {
+	return p.query.Get(key)
+}
+
+// Returns the first value from the request body (form, multipart, or JSON), or "".
+func (p *Params) Form(key string) string {
+	return p.form.Get(key)
+}
+
+// Returns the value of the route's path parameter, or "".
+func (p *Params) Route(key string) string {
+	return p.route.Get(key)
+}
+
+// Returns the first value for the key exactly as it was sent in the urlencoded
+// body or query string (preferring the body, as Get does), before
+// percent-decoding, or "" if there is none.
+// (The values in Params.Values are decoded exactly once, so a double-encoded
+// "%2520" is "%20" there.)  This is useful for debugging encoding problems and
+// for verifying signatures computed over the raw request.
+func (p *Params) RawValue(key string) string {
+	return p.raw.Get(key)
+}
+
+// Returns the first value for the key, or "" if there is none.
+func (p *Params) GetString(key string) string {
+	return p.Get(key)
+}
+
+// Returns the first value for the key as an int.
+// The second return value is false if the key is missing or not an integer.
+func (p *Params) GetInt(key string) (int, bool) {
+	i, err := strconv.Atoi(strings.TrimSpace(p.Get(key)))
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+// Returns the first value for the key as a bool.  The same spellings are
+// understood as by the bool binder (e.g. "true", "on", "yes", and "1").  The
+// second return value is false if the key is missing or not a boolean.
+func (p *Params) GetBool(key string) (bool, bool) {
+	return parseBool(p.Get(key))
+}
+
 // Get the content type.
 // e.g. From "multipart/form-data; boundary=--" to "multipart/form-data"
 // If none is specified, returns "text/html" by default.
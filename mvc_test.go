@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 )
@@ -167,3 +169,258 @@ func TestMultipartForm(t *testing.T) {
 		t.Errorf("Param files: (expected) %v != %v (actual)", expectedFiles, actualFiles)
 	}
 }
+
+// Params: Testing JSON bodies
+
+const JSON_DATA = `{
+	"name": "Johnny Test",
+	"age": 12,
+	"alive": true,
+	"nothing": null,
+	"tags": ["a", "b"],
+	"address": {"city": "Springfield", "zip": "12345"},
+	"posts": [{"id": 5}, {"id": 8}]
+}`
+
+func getJsonRequest(body string) *http.Request {
+	req, _ := http.NewRequest("POST", "http://localhost/path?q=query",
+		bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return req
+}
+
+func TestJsonParams(t *testing.T) {
+	params := ParseParams(NewRequest(getJsonRequest(JSON_DATA)))
+
+	expected := map[string][]string{
+		"q":            {"query"},
+		"name":         {"Johnny Test"},
+		"age":          {"12"},
+		"alive":        {"true"},
+		"tags[0]":      {"a"},
+		"tags[1]":      {"b"},
+		"address.city": {"Springfield"},
+		"address.zip":  {"12345"},
+		"posts[0].id":  {"5"},
+		"posts[1].id":  {"8"},
+	}
+	if !reflect.DeepEqual(expected, map[string][]string(params.Values)) {
+		t.Errorf("Param values: (expected) %v != %v (actual)",
+			expected, map[string][]string(params.Values))
+	}
+
+	eq(t, "name", Bind(params, "name", reflect.TypeOf("")).Interface(), "Johnny Test")
+	eq(t, "age", Bind(params, "age", reflect.TypeOf(0)).Interface(), 12)
+	eq(t, "alive", Bind(params, "alive", reflect.TypeOf(true)).Interface(), true)
+	valEq(t, "tags", Bind(params, "tags", reflect.TypeOf([]string{})), reflect.ValueOf([]string{"a", "b"}))
+
+	// Array elements are indexed, so they are never split.
+	defer func(sep string) { SliceSeparator = sep }(SliceSeparator)
+	SliceSeparator = ","
+	params = ParseParams(NewRequest(getJsonRequest(`{"tags": ["New York, NY"]}`)))
+	valEq(t, "tags (separator)", Bind(params, "tags", reflect.TypeOf([]string{})),
+		reflect.ValueOf([]string{"New York, NY"}))
+}
+
+func TestMalformedJsonParams(t *testing.T) {
+	params := ParseParams(NewRequest(getJsonRequest(`{"name": `)))
+	expected := map[string][]string{"q": {"query"}}
+	if !reflect.DeepEqual(expected, map[string][]string(params.Values)) {
+		t.Errorf("Param values: (expected) %v != %v (actual)",
+			expected, map[string][]string(params.Values))
+	}
+}
+
+func TestOversizedJsonParams(t *testing.T) {
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 16
+
+	params := ParseParams(NewRequest(getJsonRequest(`{"name": "Johnny Test", "age": 12}`)))
+	expected := map[string][]string{"q": {"query"}}
+	if !reflect.DeepEqual(expected, map[string][]string(params.Values)) {
+		t.Errorf("Param values: (expected) %v != %v (actual)",
+			expected, map[string][]string(params.Values))
+	}
+}
+
+func TestBodilessJsonParams(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost/path?q=query", nil)
+	req.Header.Set("Content-Type", "application/json")
+	params := ParseParams(NewRequest(req))
+	eq(t, "q", params.Get("q"), "query")
+
+	req, _ = http.NewRequest("GET", "http://localhost/path", bytes.NewBufferString(`{"name": "rob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	eq(t, "GET body", ParseParams(NewRequest(req)).Get("name"), "")
+}
+
+func TestSetRoute(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost/users/42/posts/hello?slug=query&page=2", nil)
+	params := ParseParams(NewRequest(req))
+	params.SetRoute(map[string]string{"id": "42", "slug": "hello"})
+
+	eq(t, "id", Bind(params, "id", reflect.TypeOf(0)).Interface(), 42)
+	eq(t, "slug", Bind(params, "slug", reflect.TypeOf("")).Interface(), "hello")
+	eq(t, "page", Bind(params, "page", reflect.TypeOf(0)).Interface(), 2)
+
+	empty := &Params{}
+	empty.SetRoute(map[string]string{"id": "5"})
+	eq(t, "empty id", empty.Get("id"), "5")
+}
+
+func TestParamsFromQuery(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost/search?q=golang&page=2&tag=a&tag=b", nil)
+	params := ParseParams(NewRequest(req))
+
+	eq(t, "q", Bind(params, "q", reflect.TypeOf("")).Interface(), "golang")
+	eq(t, "page", Bind(params, "page", reflect.TypeOf(0)).Interface(), 2)
+	if tags := Bind(params, "tag", reflect.TypeOf([]string{})).Interface(); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("Expected tags [a b], got %v", tags)
+	}
+}
+
+func TestParamSources(t *testing.T) {
+	body := "id=body&form=1"
+	req, _ := http.NewRequest("POST", "http://localhost/path?id=query&query=1",
+		bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	params := ParseParams(NewRequest(req))
+
+	eq(t, "Query", params.Query("id"), "query")
+	eq(t, "Form", params.Form("id"), "body")
+	eq(t, "Route (unset)", params.Route("id"), "")
+	eq(t, "Query (form key)", params.Query("form"), "")
+	eq(t, "Form (query key)", params.Form("query"), "")
+	if !reflect.DeepEqual(params.Values["id"], []string{"body", "query"}) {
+		t.Errorf("Expected the merged values, got %v", params.Values["id"])
+	}
+	eq(t, "Get (body wins)", params.Get("id"), "body")
+	eq(t, "RawValue (body wins)", params.RawValue("id"), "body")
+
+	params.SetRoute(map[string]string{"id": "route"})
+	eq(t, "Route", params.Route("id"), "route")
+	eq(t, "Query (after route)", params.Query("id"), "query")
+	eq(t, "Form (after route)", params.Form("id"), "body")
+	eq(t, "Get (after route)", params.Get("id"), "route")
+}
+
+func TestParamsRawValue(t *testing.T) {
+	body := "sig=a%2Bb&name=J%C3%BCrgen"
+	req, _ := http.NewRequest("POST", "http://localhost/path?path=%2520tmp&a+key=x+y",
+		bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	params := ParseParams(NewRequest(req))
+
+	eq(t, "path", params.Get("path"), "%20tmp")
+	eq(t, "path (raw)", params.RawValue("path"), "%2520tmp")
+	eq(t, "a key", params.Get("a key"), "x y")
+	eq(t, "a key (raw)", params.RawValue("a key"), "x+y")
+	eq(t, "sig", params.Get("sig"), "a+b")
+	eq(t, "sig (raw)", params.RawValue("sig"), "a%2Bb")
+	eq(t, "name", params.Get("name"), "Jürgen")
+	eq(t, "name (raw)", params.RawValue("name"), "J%C3%BCrgen")
+	eq(t, "missing (raw)", params.RawValue("missing"), "")
+}
+
+func TestOversizedFormBody(t *testing.T) {
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 16
+
+	req, _ := http.NewRequest("POST", "http://localhost/path?id=1",
+		bytes.NewBufferString("name=rob&padding=xxxxxxxxxxxxxxxx"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	params := ParseParams(NewRequest(req))
+
+	eq(t, "query value", params.Get("id"), "1")
+	eq(t, "form value", params.Form("name"), "")
+	eq(t, "raw form value", params.RawValue("name"), "")
+}
+
+func TestGetFormBodyIgnored(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost/path",
+		bytes.NewBufferString("name=rob"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	params := ParseParams(NewRequest(req))
+
+	eq(t, "form value", params.Get("name"), "")
+	eq(t, "raw form value", params.RawValue("name"), "")
+}
+
+func TestWithParams(t *testing.T) {
+	var received *Params
+	server := httptest.NewServer(WithParams(func(p *Params, w http.ResponseWriter, r *http.Request) {
+		received = p
+		fmt.Fprint(w, "Hello, ", p.Get("name"))
+	}))
+	defer server.Close()
+
+	resp, err := http.PostForm(server.URL+"/?source=query", url.Values{"name": {"rob"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	eq(t, "body", string(body), "Hello, rob")
+	if received == nil {
+		t.Fatal("The handler did not receive the params")
+	}
+	eq(t, "form value", received.Form("name"), "rob")
+	eq(t, "query value", received.Query("source"), "query")
+}
+
+// These use the params parsed from FORM_DATA, in validation_test.go.
+func TestParamsGetString(t *testing.T) {
+	eq(t, "name", params.GetString("name"), "Johnny Test")
+	eq(t, "blank_str", params.GetString("blank_str"), "")
+	eq(t, "fake", params.GetString("fake"), "")
+}
+
+func TestParamsGetInt(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected int
+		ok       bool
+	}{
+		{"age", 12, true},
+		{"money", 0, true},
+		{"negative", -50, true},
+		{"blank_int", 0, false},
+		{"name", 0, false},
+		{"fake", 0, false},
+	}
+	for _, test := range tests {
+		actual, ok := params.GetInt(test.key)
+		eq(t, test.key, actual, test.expected)
+		eq(t, test.key+" (ok)", ok, test.ok)
+	}
+}
+
+func TestParamsGetBool(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+		ok       bool
+	}{
+		{"boolean", true, true},
+		{"dead", false, true},
+		{"blank_bool", false, false},
+		{"name", false, false},
+		{"fake", false, false},
+	}
+	for _, test := range tests {
+		actual, ok := params.GetBool(test.key)
+		eq(t, test.key, actual, test.expected)
+		eq(t, test.key+" (ok)", ok, test.ok)
+	}
+}
+
+// GetBool should agree with the bool binder on every spelling.
+func TestParamsGetBoolMatchesBinder(t *testing.T) {
+	for _, val := range []string{"true", "t", "on", "Yes", "1", "false", "f", "OFF", "no", "0"} {
+		p := &Params{Values: map[string][]string{"b": {val}}}
+		actual, ok := p.GetBool("b")
+		eq(t, val+" (ok)", ok, true)
+		eq(t, val, actual, Bind(p, "b", reflect.TypeOf(false)).Bool())
+	}
+}
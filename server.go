@@ -4,7 +4,6 @@ import (
 	"code.google.com/p/go.net/websocket"
 	"fmt"
 	"net/http"
-	"net/url"
 	"path"
 	"reflect"
 	"time"
@@ -78,9 +77,7 @@ func handleInternal(w http.ResponseWriter, r *http.Request, ws *websocket.Conn)
 	}
 
 	// Add the route Params to the Request Params.
-	for key, value := range route.Params {
-		url.Values(controller.Params.Values).Add(key, value)
-	}
+	controller.Params.SetRoute(route.Params)
 
 	// Collect the values for the method's arguments.
 	actualArgs := BindParameters(controller)
@@ -1,13 +1,38 @@
 package rev
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type ValidationError struct {
-	Message, Key string
+	Message string `json:"message"`
+	Key     string `json:"key"`
+
+	// A stable identifier for the kind of failure, for clients that localize
+	// messages themselves.  Errors from a Check have the snake_case name of its
+	// type (e.g. "required", "min_size", or "email"); those from AddError have
+	// none unless it is set with ValidationResult.Code.
+	Code string `json:"code,omitempty"`
+
+	// The value that failed the Check (after any normalization), for logging
+	// and debugging.  It is not included in JSON output, but it may hold
+	// sensitive data such as a password, so callers that log errors should
+	// scrub the Values of sensitive fields.  Errors from AddError have none.
+	Value interface{} `json:"-"`
 }
 
 // Returns the Message.
@@ -22,20 +47,111 @@ func (e *ValidationError) String() string {
 type Validation struct {
 	Errors []*ValidationError
 	keep   bool
+
+	// If set, validation stops at the first failure: once the context has an
+	// error, every check (whether run by a method such as Required, by Check,
+	// or by Struct) is skipped.  A skipped check's result is not Ok, since the
+	// value was not validated, but has no Error; test Error to tell a skipped
+	// check from a failed one.  By default, all of the errors are collected.
+	StopOnFirst bool
+
+	// If set, string values are passed through Normalizer before they are
+	// checked.  For example, strings.TrimSpace ignores surrounding whitespace.
+	Normalizer func(string) string
+
+	// If set, string values have their whitespace collapsed by NormalizeSpace
+	// (after any Normalizer) before they are checked, so that "a   b" has the
+	// same size as "a b".
+	CollapseSpace bool
+
+	// If set, the message of each failed Check is passed through Translator
+	// before being stored, so that the default messages act as translation keys.
+	Translator func(message string) string
+
+	// Message overrides for this context, in the same form as DefaultMessages.
+	// They take precedence over DefaultMessages.
+	Messages map[string]string
+}
+
+// A Validator creates Validation contexts that share the same configuration,
+// so that (for example) localization may be set up once for the application:
+//
+//   var validator = rev.Validator{Messages: messages, Translator: translate}
+//   ...
+//   v := validator.New()
+type Validator struct {
+	Messages   map[string]string
+	Translator func(message string) string
+}
+
+// Returns a new Validation context with the Validator's configuration.
+func (f Validator) New() *Validation {
+	return &Validation{
+		Messages:   f.Messages,
+		Translator: f.Translator,
+	}
+}
+
+var validationPool = sync.Pool{
+	New: func() interface{} {
+		return &Validation{Errors: make([]*ValidationError, 0, 5)}
+	},
+}
+
+// Returns an empty Validation context from a pool, to reduce allocations in
+// high-throughput handlers.  Return it with ReleaseValidation when done.
+func AcquireValidation() *Validation {
+	return validationPool.Get().(*Validation)
+}
+
+// Reset the Validation context and return it to the pool.  The caller must not
+// use it (or its Errors) afterwards.  Contexts that have been kept (by calling
+// Keep) are left alone, since their errors are still needed.
+func ReleaseValidation(v *Validation) {
+	if v == nil || v.keep {
+		return
+	}
+	for i := range v.Errors {
+		v.Errors[i] = nil
+	}
+	*v = Validation{Errors: v.Errors[:0]}
+	validationPool.Put(v)
 }
 
 func (v *Validation) Keep() {
 	v.keep = true
 }
 
+// Remove all of the errors, and undo any call to Keep.
 func (v *Validation) Clear() {
 	v.Errors = []*ValidationError{}
+	v.keep = false
 }
 
 func (v *Validation) HasErrors() bool {
 	return len(v.Errors) > 0
 }
 
+// Returns the number of errors.
+func (v *Validation) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.Errors)
+}
+
+// Returns the errors as "key: message" lines, for logging and test output.
+func (v *Validation) String() string {
+	if v == nil {
+		return ""
+	}
+	lines := make([]string, len(v.Errors))
+	for i, e := range v.Errors {
+		lines[i] = e.Key + ": " + strings.TrimRight(e.Message, "\n")
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Return the errors mapped by key.
 // If there are multiple validation errors associated with a single key, the
 // first one "wins".  (Typically the first validation will be the more basic).
@@ -49,17 +165,139 @@ func (v *Validation) ErrorMap() map[string]*ValidationError {
 	return m
 }
 
+// Return all of the errors mapped by key, in the order they were added.
+func (v *Validation) ErrorsByKey() map[string][]*ValidationError {
+	m := map[string][]*ValidationError{}
+	for _, e := range v.Errors {
+		m[e.Key] = append(m[e.Key], e)
+	}
+	return m
+}
+
+// A snapshot of a validation context's errors, for templates, which would
+// otherwise rebuild the ErrorMap every time they look up a key.
+type ValidationSummary struct {
+	HasErrors bool
+	Errors    []*ValidationError
+	ByKey     map[string]*ValidationError // The first error for each key.
+}
+
+// Returns a summary of the current errors.  It does not reflect errors that
+// are added (or cleared) afterwards.
+func (v *Validation) Result() ValidationSummary {
+	return ValidationSummary{
+		HasErrors: v.HasErrors(),
+		Errors:    append([]*ValidationError(nil), v.Errors...),
+		ByKey:     v.ErrorMap(),
+	}
+}
+
+// Returns the errors as a JSON array of {"message": ..., "key": ..., "code": ...}
+// objects, for API responses.  A context without errors results in [].
+func (v *Validation) ErrorsJSON() ([]byte, error) {
+	errors := []*ValidationError{}
+	if v != nil {
+		errors = append(errors, v.Errors...)
+	}
+	return json.Marshal(errors)
+}
+
+// Returns true if there are any errors for the given key.
+func (v *Validation) HasError(key string) bool {
+	return v.Error(key) != nil
+}
+
+// Returns the first error for the given key, or nil if there are none.
+// (This is the same error that ErrorMap returns for the key).
+func (v *Validation) Error(key string) *ValidationError {
+	for _, e := range v.Errors {
+		if e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// Append the errors from another validation context, preserving their order.
+func (v *Validation) Merge(other *Validation) {
+	if other != nil {
+		v.Errors = append(v.Errors, other.Errors...)
+	}
+}
+
+// Remove all of the errors for the given key.
+func (v *Validation) ClearKey(key string) {
+	errors := v.Errors[:0]
+	for _, e := range v.Errors {
+		if e.Key != key {
+			errors = append(errors, e)
+		}
+	}
+	v.Errors = errors
+}
+
+// Remove the errors for all but the given keys, e.g. to retain only the errors
+// for the current step of a multi-step form.
+func (v *Validation) KeepKeys(keys ...string) {
+	errors := v.Errors[:0]
+	for _, e := range v.Errors {
+		if ContainsString(keys, e.Key) {
+			errors = append(errors, e)
+		}
+	}
+	v.Errors = errors
+}
+
+// Returns a copy of the validation context, with its own copy of each error.
+func (v *Validation) Copy() *Validation {
+	c := *v
+	c.Errors = make([]*ValidationError, len(v.Errors))
+	for i, e := range v.Errors {
+		err := *e
+		c.Errors[i] = &err
+	}
+	return &c
+}
+
+// Add an error to the validation context, for failures that are not
+// expressed as a Check (e.g. a username that is already taken).
+func (v *Validation) AddError(key, message string) *ValidationResult {
+	err := &ValidationError{
+		Message: message,
+		Key:     key,
+	}
+	v.Errors = append(v.Errors, err)
+	return &ValidationResult{
+		Ok:    false,
+		Error: err,
+	}
+}
+
 // A ValidationResult is returned from every validation method.
 // It provides an indication of success, and a pointer to the Error (if any).
 type ValidationResult struct {
 	Error *ValidationError
 	Ok    bool
+
+	// For Each, the errors of the elements that failed.  Error is the first.
+	elements []elementError
+}
+
+// The error for a failed element of a slice, and its index.
+type elementError struct {
+	*ValidationError
+	index int
 }
 
+// Set the key of the error.  For Each, each element's error is keyed by the
+// key and its index, e.g. "emails[2]".
 func (r *ValidationResult) Key(key string) *ValidationResult {
 	if r.Error != nil {
 		r.Error.Key = key
 	}
+	for _, e := range r.elements {
+		e.Key = fmt.Sprintf("%s[%d]", key, e.index)
+	}
 	return r
 }
 
@@ -67,9 +305,28 @@ func (r *ValidationResult) Message(message string) *ValidationResult {
 	if r.Error != nil {
 		r.Error.Message = message
 	}
+	for _, e := range r.elements {
+		e.Message = message
+	}
+	return r
+}
+
+// Override the error's Code.
+func (r *ValidationResult) Code(code string) *ValidationResult {
+	if r.Error != nil {
+		r.Error.Code = code
+	}
+	for _, e := range r.elements {
+		e.Code = code
+	}
 	return r
 }
 
+// Like Message, but formats the message with fmt.Sprintf.
+func (r *ValidationResult) Messagef(format string, args ...interface{}) *ValidationResult {
+	return r.Message(fmt.Sprintf(format, args...))
+}
+
 type Check interface {
 	IsSatisfied(interface{}) bool
 	DefaultMessage() string
@@ -77,7 +334,9 @@ type Check interface {
 
 /*
 	Required validator. Use to ensure that a parameter is present in the request parameters and
-	is not empty. Empty strings, slices, and zero dates are considered empty.
+	is not empty. Empty strings, slices, maps, and zero dates are considered empty.
+	Nil pointers are empty too, and other pointers are required to point to a
+	value that is not empty.
 */
 type Required struct{}
 
@@ -95,6 +354,15 @@ func (r Required) IsSatisfied(obj interface{}) bool {
 	if t, ok := obj.(time.Time); ok {
 		return !t.IsZero()
 	}
+	switch val := reflect.ValueOf(obj); val.Kind() {
+	case reflect.Map:
+		return val.Len() > 0
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return false
+		}
+		return r.IsSatisfied(val.Elem().Interface())
+	}
 	return true
 }
 
@@ -106,17 +374,95 @@ func (v *Validation) Required(obj interface{}) *ValidationResult {
 	return v.check(Required{}, obj)
 }
 
+// Bind the named parameter to the given type, and require it.
+// Any error is keyed by the parameter name.
+func (v *Validation) RequiredParam(params *Params, name string, typ reflect.Type) *ValidationResult {
+	return v.Required(Bind(params, name, typ).Interface()).Key(name)
+}
+
+// Requires the string to be non-empty after trimming surrounding whitespace.
+func (v *Validation) RequiredTrimmed(str string) *ValidationResult {
+	return v.check(Required{}, strings.TrimSpace(str))
+}
+
+// Like Required, but a string is trimmed of surrounding whitespace first, so
+// that one consisting only of whitespace fails.  Other values are checked
+// exactly as by Required.
+type NonBlank struct{}
+
+func (n NonBlank) IsSatisfied(obj interface{}) bool {
+	if str, ok := obj.(string); ok {
+		return strings.TrimSpace(str) != ""
+	}
+	return Required{}.IsSatisfied(obj)
+}
+
+func (n NonBlank) DefaultMessage() string {
+	return "Required"
+}
+
+func (v *Validation) NonBlank(obj interface{}) *ValidationResult {
+	return v.check(NonBlank{}, obj)
+}
+
+// Requires a value to be true, e.g. an "I agree to the terms" checkbox.  A
+// string is accepted if it is one of the spellings of true that the bool
+// binder accepts ("true", "on", "yes", or "1", in any case).
+type Accepted struct{}
+
+func (a Accepted) IsSatisfied(obj interface{}) bool {
+	switch obj := obj.(type) {
+	case bool:
+		return obj
+	case string:
+		return BindValue(obj, reflect.TypeOf(true)).Bool()
+	}
+	return false
+}
+
+func (a Accepted) DefaultMessage() string {
+	return "Must be accepted"
+}
+
+func (v *Validation) Accepted(b bool) *ValidationResult {
+	return v.check(Accepted{}, b)
+}
+
+// Requires obj only if cond is true.  Otherwise, the result is always Ok.
+// e.g. v.RequiredIf(otherReason, reason == "other")
+func (v *Validation) RequiredIf(obj interface{}, cond bool) *ValidationResult {
+	if !cond {
+		return &ValidationResult{Ok: true}
+	}
+	return v.Required(obj)
+}
+
+// Requires obj only if a companion field is present.
+// e.g. v.RequiredWith(zip, address != "")
+func (v *Validation) RequiredWith(obj interface{}, otherPresent bool) *ValidationResult {
+	return v.RequiredIf(obj, otherPresent)
+}
+
+// Requires obj only if a companion field is absent.  Together, these express
+// "either phone or email is required":
+//
+//   v.RequiredWithout(phone, email != "").Key("phone")
+//   v.RequiredWithout(email, phone != "").Key("email")
+func (v *Validation) RequiredWithout(obj interface{}, otherPresent bool) *ValidationResult {
+	return v.RequiredIf(obj, !otherPresent)
+}
+
 /*
-	Min validator. Use to ensure that a parameter is an integer not less than a certain number.
+	Min validator. Use to ensure that a parameter is a number not less than a certain number.
 */
 type Min struct {
 	Min int
 }
 
 func (m Min) IsSatisfied(obj interface{}) bool {
-	num, ok := obj.(int)
+	num, ok := toFloat64(obj)
 	if ok {
-		return num >= m.Min
+		return num >= float64(m.Min)
 	}
 	return false
 }
@@ -125,21 +471,21 @@ func (m Min) DefaultMessage() string {
 	return fmt.Sprintln("Minimum is", m.Min)
 }
 
-func (v *Validation) Min(n int, min int) *ValidationResult {
+func (v *Validation) Min(n interface{}, min int) *ValidationResult {
 	return v.check(Min{min}, n)
 }
 
 /*
-	Max validator. Use to ensure that a parameter is an integer not greater than a certain number.
+	Max validator. Use to ensure that a parameter is a number not greater than a certain number.
 */
 type Max struct {
 	Max int
 }
 
 func (m Max) IsSatisfied(obj interface{}) bool {
-	num, ok := obj.(int)
+	num, ok := toFloat64(obj)
 	if ok {
-		return num <= m.Max
+		return num <= float64(m.Max)
 	}
 	return false
 }
@@ -148,12 +494,12 @@ func (m Max) DefaultMessage() string {
 	return fmt.Sprintln("Maximum is", m.Max)
 }
 
-func (v *Validation) Max(n int, max int) *ValidationResult {
+func (v *Validation) Max(n interface{}, max int) *ValidationResult {
 	return v.check(Max{max}, n)
 }
 
 /*
-	Range validator. Use to ensure that a parameter is an int within an inclusive integer interval.
+	Range validator. Use to ensure that a parameter is a number within an inclusive integer interval.
 */
 type Range struct {
 	Min int
@@ -161,9 +507,9 @@ type Range struct {
 }
 
 func (r Range) IsSatisfied(obj interface{}) bool {
-	num, ok := obj.(int)
+	num, ok := toFloat64(obj)
 	if ok {
-		return r.Min <= num && num <= r.Max
+		return float64(r.Min) <= num && num <= float64(r.Max)
 	}
 	return false
 }
@@ -172,10 +518,177 @@ func (r Range) DefaultMessage() string {
 	return fmt.Sprintf("Valid range is %d to %d, inclusive.", r.Min, r.Max)
 }
 
-func (v *Validation) Range(n int, min, max int) *ValidationResult {
+func (v *Validation) Range(n interface{}, min, max int) *ValidationResult {
 	return v.check(Range{min, max}, n)
 }
 
+var numberPattern = regexp.MustCompile(`^[-+]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?$`)
+
+// Parses a string holding a decimal number (e.g. a form value like "20" or
+// "-1.5").  Returns false if obj is not such a string, so that a string like
+// "abc" fails the check, rather than being treated as 0.
+func parseNumber(obj interface{}) (float64, bool) {
+	str, ok := obj.(string)
+	if !ok {
+		return 0, false
+	}
+	str = strings.TrimSpace(str)
+	if !numberPattern.MatchString(str) {
+		return 0, false
+	}
+	num, err := strconv.ParseFloat(str, 64)
+	return num, err == nil
+}
+
+// Like Min, but for a number held in a string, so that a form value may be
+// checked before it is bound.  Fails if the string is not a number.
+type MinString struct {
+	Min int
+}
+
+func (m MinString) IsSatisfied(obj interface{}) bool {
+	num, ok := parseNumber(obj)
+	return ok && Min{m.Min}.IsSatisfied(num)
+}
+
+func (m MinString) DefaultMessage() string {
+	return Min{m.Min}.DefaultMessage()
+}
+
+func (v *Validation) MinString(str string, min int) *ValidationResult {
+	return v.check(MinString{min}, str)
+}
+
+// Like Max, but for a number held in a string.  Fails if the string is not a
+// number.
+type MaxString struct {
+	Max int
+}
+
+func (m MaxString) IsSatisfied(obj interface{}) bool {
+	num, ok := parseNumber(obj)
+	return ok && Max{m.Max}.IsSatisfied(num)
+}
+
+func (m MaxString) DefaultMessage() string {
+	return Max{m.Max}.DefaultMessage()
+}
+
+func (v *Validation) MaxString(str string, max int) *ValidationResult {
+	return v.check(MaxString{max}, str)
+}
+
+// Like Range, but for a number held in a string.  Fails if the string is not
+// a number.
+type RangeString struct {
+	Min int
+	Max int
+}
+
+func (r RangeString) IsSatisfied(obj interface{}) bool {
+	num, ok := parseNumber(obj)
+	return ok && Range{r.Min, r.Max}.IsSatisfied(num)
+}
+
+func (r RangeString) DefaultMessage() string {
+	return Range{r.Min, r.Max}.DefaultMessage()
+}
+
+func (v *Validation) RangeString(str string, min, max int) *ValidationResult {
+	return v.check(RangeString{min, max}, str)
+}
+
+// Requires a number to be within an inclusive floating point interval.
+// Ints are accepted as well as floats.
+type FloatRange struct {
+	Min float64
+	Max float64
+}
+
+func (r FloatRange) IsSatisfied(obj interface{}) bool {
+	num, ok := toFloat64(obj)
+	if ok {
+		return r.Min <= num && num <= r.Max
+	}
+	return false
+}
+
+func (r FloatRange) DefaultMessage() string {
+	return fmt.Sprintf("Valid range is %g to %g, inclusive.", r.Min, r.Max)
+}
+
+func (v *Validation) FloatRange(n interface{}, min, max float64) *ValidationResult {
+	return v.check(FloatRange{min, max}, n)
+}
+
+// Requires a number to be a valid latitude, between -90 and 90 degrees.
+type Latitude struct{}
+
+func (l Latitude) IsSatisfied(obj interface{}) bool {
+	return FloatRange{-90, 90}.IsSatisfied(obj)
+}
+
+func (l Latitude) DefaultMessage() string {
+	return "Latitude must be between -90 and 90"
+}
+
+func (v *Validation) Latitude(n interface{}) *ValidationResult {
+	return v.check(Latitude{}, n)
+}
+
+// Requires a number to be a valid longitude, between -180 and 180 degrees.
+type Longitude struct{}
+
+func (l Longitude) IsSatisfied(obj interface{}) bool {
+	return FloatRange{-180, 180}.IsSatisfied(obj)
+}
+
+func (l Longitude) DefaultMessage() string {
+	return "Longitude must be between -180 and 180"
+}
+
+func (v *Validation) Longitude(n interface{}) *ValidationResult {
+	return v.check(Longitude{}, n)
+}
+
+// Requires a number to be strictly greater than N.  Ints are accepted as well
+// as floats.  e.g. GreaterThan{0} for a strictly positive amount.
+type GreaterThan struct {
+	N float64
+}
+
+func (g GreaterThan) IsSatisfied(obj interface{}) bool {
+	num, ok := toFloat64(obj)
+	return ok && num > g.N
+}
+
+func (g GreaterThan) DefaultMessage() string {
+	return fmt.Sprintf("Must be greater than %g", g.N)
+}
+
+func (v *Validation) GreaterThan(x interface{}, n float64) *ValidationResult {
+	return v.check(GreaterThan{n}, x)
+}
+
+// Requires a number to be strictly less than N.  Ints are accepted as well as
+// floats.
+type LessThan struct {
+	N float64
+}
+
+func (l LessThan) IsSatisfied(obj interface{}) bool {
+	num, ok := toFloat64(obj)
+	return ok && num < l.N
+}
+
+func (l LessThan) DefaultMessage() string {
+	return fmt.Sprintf("Must be less than %g", l.N)
+}
+
+func (v *Validation) LessThan(x interface{}, n float64) *ValidationResult {
+	return v.check(LessThan{n}, x)
+}
+
 /*
 	Positive validator. Use to ensure that a parameter is a positive integer.
 */
@@ -219,37 +732,166 @@ func (v *Validation) Negative(n int) *ValidationResult {
 }
 
 /*
-	NonZero validator. Use to ensure that a parameter is a non-zero integer.
+	NonZero validator. Use to ensure that a parameter is not the zero value of its type.
+	This is stricter than Required: 0, "", nil, zero structs, and empty maps and slices all fail.
 */
 type NonZero struct{}
 
 func (p NonZero) IsSatisfied(obj interface{}) bool {
-	num, ok := obj.(int)
-	if ok {
-		return num != 0
+	val := reflect.ValueOf(obj)
+	switch val.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Map, reflect.Slice:
+		return val.Len() > 0
 	}
-	return false
+	return !val.IsZero()
 }
 
 func (p NonZero) DefaultMessage() string {
-	return fmt.Sprintln("Number must not be equal to 0.")
+	return fmt.Sprintln("Must not be zero or empty.")
+}
+
+func (v *Validation) NonZero(obj interface{}) *ValidationResult {
+	return v.check(NonZero{}, obj)
+}
+
+// Requires a time.Time to be within an inclusive interval.
+// A zero Min or Max leaves the interval unbounded on that side.
+type DateRange struct {
+	Min time.Time
+	Max time.Time
+}
+
+func (r DateRange) IsSatisfied(obj interface{}) bool {
+	t, ok := obj.(time.Time)
+	if !ok {
+		return false
+	}
+	return (r.Min.IsZero() || !t.Before(r.Min)) && (r.Max.IsZero() || !t.After(r.Max))
+}
+
+// The layout used to format times in validation messages.
+const messageTimeLayout = "2006-01-02 15:04"
+
+func (r DateRange) DefaultMessage() string {
+	const layout = messageTimeLayout
+	switch {
+	case r.Min.IsZero() && r.Max.IsZero():
+		return "Must be a valid date"
+	case r.Min.IsZero():
+		return "Must be on or before " + r.Max.Format(layout)
+	case r.Max.IsZero():
+		return "Must be on or after " + r.Min.Format(layout)
+	}
+	return fmt.Sprintf("Must be between %s and %s", r.Min.Format(layout), r.Max.Format(layout))
+}
+
+func (v *Validation) DateRange(t, min, max time.Time) *ValidationResult {
+	return v.check(DateRange{min, max}, t)
+}
+
+// Requires a time to be strictly before Ref.  A zero time is treated as
+// missing and fails.
+type Before struct {
+	Ref time.Time
+}
+
+func (b Before) IsSatisfied(obj interface{}) bool {
+	t, ok := obj.(time.Time)
+	return ok && !t.IsZero() && t.Before(b.Ref)
+}
+
+func (b Before) DefaultMessage() string {
+	return "Must be before " + b.Ref.Format(messageTimeLayout)
+}
+
+func (v *Validation) Before(t, reference time.Time) *ValidationResult {
+	return v.check(Before{reference}, t)
 }
 
-func (v *Validation) NonZero(n int) *ValidationResult {
-	return v.check(NonZero{}, n)
+// Requires a time to be strictly after Ref.  A zero time is treated as
+// missing and fails.
+type After struct {
+	Ref time.Time
 }
 
-// Requires an array or string to be at least a given length.
+func (a After) IsSatisfied(obj interface{}) bool {
+	t, ok := obj.(time.Time)
+	return ok && !t.IsZero() && t.After(a.Ref)
+}
+
+func (a After) DefaultMessage() string {
+	return "Must be after " + a.Ref.Format(messageTimeLayout)
+}
+
+func (v *Validation) After(t, reference time.Time) *ValidationResult {
+	return v.check(After{reference}, t)
+}
+
+// Requires a string to be a valid time in the given Layout (in the form used
+// by time.Parse, e.g. "2006-01-02").  Impossible dates, like February 30th,
+// are rejected.
+type DateFormat struct {
+	Layout string
+}
+
+func (d DateFormat) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(d.Layout, str)
+	return err == nil
+}
+
+func (d DateFormat) DefaultMessage() string {
+	return "Must be a date in the format " + d.Layout
+}
+
+func (v *Validation) DateFormat(str, layout string) *ValidationResult {
+	return v.check(DateFormat{layout}, str)
+}
+
+// Requires a string to fall between Min and Max (inclusive) in lexical
+// (byte-wise) order.  An empty bound means unbounded on that side.
+type StringRange struct {
+	Min, Max string
+}
+
+func (r StringRange) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	return (r.Min == "" || str >= r.Min) && (r.Max == "" || str <= r.Max)
+}
+
+func (r StringRange) DefaultMessage() string {
+	switch {
+	case r.Min == "" && r.Max == "":
+		return "Must be a string"
+	case r.Min == "":
+		return fmt.Sprintf("Must be at most %q", r.Max)
+	case r.Max == "":
+		return fmt.Sprintf("Must be at least %q", r.Min)
+	}
+	return fmt.Sprintf("Must be between %q and %q, inclusive", r.Min, r.Max)
+}
+
+func (v *Validation) StringRange(str, min, max string) *ValidationResult {
+	return v.check(StringRange{min, max}, str)
+}
+
+// Requires a string, slice, array, or map to be at least a given length.
+// The length of a string is its number of characters (runes), not bytes.
 type MinSize struct {
 	Min int
 }
 
 func (m MinSize) IsSatisfied(obj interface{}) bool {
-	if arr, ok := obj.([]interface{}); ok {
-		return len(arr) >= m.Min
-	}
-	if str, ok := obj.(string); ok {
-		return len(str) >= m.Min
+	if size, ok := sizeOf(obj); ok {
+		return size >= m.Min
 	}
 	return false
 }
@@ -262,17 +904,15 @@ func (v *Validation) MinSize(obj interface{}, min int) *ValidationResult {
 	return v.check(MinSize{min}, obj)
 }
 
-// Requires an array or string to be at most a given length.
+// Requires a string, slice, array, or map to be at most a given length.
+// The length of a string is its number of characters (runes), not bytes.
 type MaxSize struct {
 	Max int
 }
 
 func (m MaxSize) IsSatisfied(obj interface{}) bool {
-	if arr, ok := obj.([]interface{}); ok {
-		return len(arr) <= m.Max
-	}
-	if str, ok := obj.(string); ok {
-		return len(str) <= m.Max
+	if size, ok := sizeOf(obj); ok {
+		return size <= m.Max
 	}
 	return false
 }
@@ -285,32 +925,1467 @@ func (v *Validation) MaxSize(obj interface{}, max int) *ValidationResult {
 	return v.check(MaxSize{max}, obj)
 }
 
-// Requires a string to match a given regex.
+// Requires a string, slice, array, or map to be exactly a given length.
+// The length of a string is its number of characters (runes), not bytes.
+type Length struct {
+	N int
+}
+
+func (l Length) IsSatisfied(obj interface{}) bool {
+	if size, ok := sizeOf(obj); ok {
+		return size == l.N
+	}
+	return false
+}
+
+func (l Length) DefaultMessage() string {
+	return fmt.Sprintln("Required length is", l.N)
+}
+
+func (v *Validation) Length(obj interface{}, n int) *ValidationResult {
+	return v.check(Length{n}, obj)
+}
+
+// Requires a string to contain at least Min words, separated by whitespace.
+// Runs of whitespace, and whitespace at either end, do not add to the count.
+type MinWords struct {
+	Min int
+}
+
+func (m MinWords) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && len(strings.Fields(str)) >= m.Min
+}
+
+func (m MinWords) DefaultMessage() string {
+	return fmt.Sprintf("Must contain at least %d words", m.Min)
+}
+
+func (v *Validation) MinWords(str string, min int) *ValidationResult {
+	return v.check(MinWords{min}, str)
+}
+
+// Requires a string, slice, array, or map to have a length between Min and
+// Max, inclusive, as MinSize and MaxSize together would, but with a single
+// error.  A Max of -1 means there is no upper bound.
+type Size struct {
+	Min, Max int
+}
+
+func (s Size) IsSatisfied(obj interface{}) bool {
+	if size, ok := sizeOf(obj); ok {
+		return size >= s.Min && (s.Max == -1 || size <= s.Max)
+	}
+	return false
+}
+
+func (s Size) DefaultMessage() string {
+	if s.Max == -1 {
+		return fmt.Sprintf("Length must be at least %d", s.Min)
+	}
+	return fmt.Sprintf("Length must be between %d and %d", s.Min, s.Max)
+}
+
+func (v *Validation) Size(obj interface{}, min, max int) *ValidationResult {
+	return v.check(Size{min, max}, obj)
+}
+
+// Requires a string (or []byte) to be between Min and Max bytes long,
+// inclusive, e.g. to fit a database column of a fixed byte size.  Unlike
+// MinSize and MaxSize, this counts bytes rather than characters.  A Max of 0
+// means there is no upper bound.
+type ByteSize struct {
+	Min, Max int
+}
+
+func (b ByteSize) IsSatisfied(obj interface{}) bool {
+	var size int
+	switch obj := obj.(type) {
+	case string:
+		size = len(obj)
+	case []byte:
+		size = len(obj)
+	default:
+		return false
+	}
+	return size >= b.Min && (b.Max == 0 || size <= b.Max)
+}
+
+func (b ByteSize) DefaultMessage() string {
+	if b.Max == 0 {
+		return fmt.Sprintf("Must be at least %d bytes", b.Min)
+	}
+	return fmt.Sprintf("Must be between %d and %d bytes", b.Min, b.Max)
+}
+
+func (v *Validation) ByteSize(str string, min, max int) *ValidationResult {
+	return v.check(ByteSize{min, max}, str)
+}
+
+// Returns the length of a string (in runes), slice, array, or map.
+// Returns false if obj is none of those.
+func sizeOf(obj interface{}) (int, bool) {
+	val := reflect.ValueOf(obj)
+	switch val.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(val.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return val.Len(), true
+	}
+	return 0, false
+}
+
+// Requires a string to match a given regex.
+// A []byte or fmt.Stringer is also accepted; any other type fails.
 type Match struct {
 	Regexp *regexp.Regexp
 }
 
-func (m Match) IsSatisfied(obj interface{}) bool {
-	str := obj.(string)
-	return m.Regexp.MatchString(str)
+func (m Match) IsSatisfied(obj interface{}) bool {
+	if str, ok := toString(obj); ok {
+		return m.Regexp.MatchString(str)
+	}
+	return false
+}
+
+func (m Match) DefaultMessage() string {
+	return fmt.Sprintln("Must match", m.Regexp)
+}
+
+func (v *Validation) Match(str string, regex *regexp.Regexp) *ValidationResult {
+	return v.check(Match{regex}, str)
+}
+
+var (
+	patternCache     = make(map[string]*regexp.Regexp)
+	patternCacheLock sync.RWMutex
+)
+
+// MatchPattern returns a Match check for the given pattern, compiling it only
+// the first time it is seen, so that it is cheap to call in a request handler
+// (and it is safe to call from concurrent handlers):
+//
+//   v.Check(str, rev.MatchPattern(`^\d+$`))
+//
+// It panics if the pattern does not compile.
+func MatchPattern(pattern string) Check {
+	patternCacheLock.RLock()
+	re, ok := patternCache[pattern]
+	patternCacheLock.RUnlock()
+	if ok {
+		return Match{re}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("MatchPattern: invalid pattern %q: %s", pattern, err))
+	}
+	patternCacheLock.Lock()
+	patternCache[pattern] = re
+	patternCacheLock.Unlock()
+	return Match{re}
+}
+
+// Requires a string to match at least one of a set of Regexps, e.g. several
+// accepted formats for the same value.  An empty set matches nothing.
+type MatchAny struct {
+	Regexps []*regexp.Regexp
+}
+
+func (m MatchAny) IsSatisfied(obj interface{}) bool {
+	str, ok := toString(obj)
+	if !ok {
+		return false
+	}
+	for _, re := range m.Regexps {
+		if re.MatchString(str) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MatchAny) DefaultMessage() string {
+	return "Invalid format"
+}
+
+func (v *Validation) MatchAny(str string, regexps ...*regexp.Regexp) *ValidationResult {
+	return v.check(MatchAny{regexps}, str)
+}
+
+// Requires a string to contain a given substring.
+type Contains struct {
+	Sub string
+}
+
+func (c Contains) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && strings.Contains(str, c.Sub)
+}
+
+func (c Contains) DefaultMessage() string {
+	return fmt.Sprintln("Must contain", c.Sub)
+}
+
+func (v *Validation) Contains(str, sub string) *ValidationResult {
+	return v.check(Contains{sub}, str)
+}
+
+// Requires a string to start with a given prefix.
+type HasPrefix struct {
+	Prefix string
+}
+
+func (h HasPrefix) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && strings.HasPrefix(str, h.Prefix)
+}
+
+func (h HasPrefix) DefaultMessage() string {
+	return fmt.Sprintln("Must start with", h.Prefix)
+}
+
+func (v *Validation) HasPrefix(str, prefix string) *ValidationResult {
+	return v.check(HasPrefix{prefix}, str)
+}
+
+// Requires a string to end with a given suffix.
+type HasSuffix struct {
+	Suffix string
+}
+
+func (h HasSuffix) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && strings.HasSuffix(str, h.Suffix)
+}
+
+func (h HasSuffix) DefaultMessage() string {
+	return fmt.Sprintln("Must end with", h.Suffix)
+}
+
+func (v *Validation) HasSuffix(str, suffix string) *ValidationResult {
+	return v.check(HasSuffix{suffix}, str)
+}
+
+var emailPattern = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+" +
+	"@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?" +
+	"(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\\.[a-zA-Z]{2,}$")
+
+// Requires a string to be a valid email address.
+// Common forms (including plus-addressing) are accepted, but the check is not
+// a complete implementation of RFC 5322.
+type Email struct{}
+
+func (e Email) IsSatisfied(obj interface{}) bool {
+	if str, ok := obj.(string); ok {
+		return emailPattern.MatchString(str)
+	}
+	return false
+}
+
+func (e Email) DefaultMessage() string {
+	return "Must be a valid email address"
+}
+
+func (v *Validation) Email(str string) *ValidationResult {
+	return v.check(Email{}, str)
+}
+
+// Requires a string to be an absolute URL with a host, using one of the
+// allowed Schemes.  If no Schemes are given, http and https are allowed.
+type URL struct {
+	Schemes []string
+}
+
+func (u URL) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+
+	parsed, err := url.Parse(str)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return false
+	}
+
+	schemes := u.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(parsed.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u URL) DefaultMessage() string {
+	return "Must be a valid URL"
+}
+
+func (v *Validation) URL(str string, schemes ...string) *ValidationResult {
+	return v.check(URL{schemes}, str)
+}
+
+var hostnameLabelPattern = regexp.MustCompile("^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$")
+
+// Requires a string to be a hostname as defined by RFC 1123 (without a scheme
+// or port): dot-separated labels of 1 to 63 letters, digits, and hyphens, that
+// do not begin or end with a hyphen, and at most 253 characters in total.
+type Hostname struct{}
+
+func (h Hostname) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok || str == "" || len(str) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(str, ".") {
+		if !hostnameLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h Hostname) DefaultMessage() string {
+	return "Must be a valid hostname"
+}
+
+func (v *Validation) Hostname(str string) *ValidationResult {
+	return v.check(Hostname{}, str)
+}
+
+// IP address versions, for use with IPAddr.
+const (
+	IPv4 = 4
+	IPv6 = 6
+)
+
+// Requires a string to be an IP address of one of the given Versions (IPv4
+// and/or IPv6).  If no Versions are given, both are accepted.
+type IPAddr struct {
+	Versions []int
+}
+
+func (i IPAddr) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok || net.ParseIP(str) == nil {
+		return false
+	}
+	if len(i.Versions) == 0 {
+		return true
+	}
+
+	// IPv6 addresses in the IPv4-mapped form (::ffff:1.2.3.4) are still IPv6.
+	version := IPv4
+	if strings.Contains(str, ":") {
+		version = IPv6
+	}
+	for _, v := range i.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (i IPAddr) DefaultMessage() string {
+	versions := i.Versions
+	if len(versions) == 0 {
+		versions = []int{IPv4, IPv6}
+	}
+	names := make([]string, len(versions))
+	for j, v := range versions {
+		names[j] = fmt.Sprint("IPv", v)
+	}
+	return "Must be a valid " + strings.Join(names, " or ") + " address"
+}
+
+func (v *Validation) IPAddr(str string, versions ...int) *ValidationResult {
+	return v.check(IPAddr{versions}, str)
+}
+
+// Requires a string to be a 48-bit (MAC-48/EUI-48) or 64-bit (EUI-64)
+// hardware address, in any of the forms accepted by net.ParseMAC, e.g.
+// "01:23:45:67:89:ab", "01-23-45-67-89-ab", or "0123.4567.89ab".
+type MAC struct{}
+
+func (m MAC) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	hw, err := net.ParseMAC(str)
+	return err == nil && (len(hw) == 6 || len(hw) == 8)
+}
+
+func (m MAC) DefaultMessage() string {
+	return "Must be a valid MAC address"
+}
+
+func (v *Validation) MAC(str string) *ValidationResult {
+	return v.check(MAC{}, str)
+}
+
+var (
+	numericPattern      = regexp.MustCompile("^[0-9]+$")
+	alphaPattern        = regexp.MustCompile("^[a-zA-Z]+$")
+	alphaNumericPattern = regexp.MustCompile("^[a-zA-Z0-9]+$")
+)
+
+// Requires a string to consist only of the digits 0-9.
+// Signs, decimal points, and the empty string are not accepted.
+type Numeric struct{}
+
+func (n Numeric) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && numericPattern.MatchString(str)
+}
+
+func (n Numeric) DefaultMessage() string {
+	return "Must contain only digits"
+}
+
+func (v *Validation) Numeric(str string) *ValidationResult {
+	return v.check(Numeric{}, str)
+}
+
+// Requires a string to consist only of the letters a-z and A-Z.
+type Alpha struct{}
+
+func (a Alpha) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && alphaPattern.MatchString(str)
+}
+
+func (a Alpha) DefaultMessage() string {
+	return "Must contain only letters"
+}
+
+func (v *Validation) Alpha(str string) *ValidationResult {
+	return v.check(Alpha{}, str)
+}
+
+// Requires a string to consist only of the letters a-z and A-Z, and the digits 0-9.
+type AlphaNumeric struct{}
+
+func (a AlphaNumeric) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && alphaNumericPattern.MatchString(str)
+}
+
+func (a AlphaNumeric) DefaultMessage() string {
+	return "Must contain only letters and digits"
+}
+
+func (v *Validation) AlphaNumeric(str string) *ValidationResult {
+	return v.check(AlphaNumeric{}, str)
+}
+
+var slugPattern = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+
+// Requires a string to be a URL-safe slug: lowercase letters and digits,
+// separated by single hyphens (e.g. "my-post-123").
+type Slug struct{}
+
+func (s Slug) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && slugPattern.MatchString(str)
+}
+
+func (s Slug) DefaultMessage() string {
+	return "Must be a valid slug"
+}
+
+func (v *Validation) Slug(str string) *ValidationResult {
+	return v.check(Slug{}, str)
+}
+
+// Requires a string to have no uppercase letters.  Other characters, such as
+// digits and punctuation, are allowed, so the empty string is lowercase.
+type LowerCase struct{}
+
+func (l LowerCase) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && str == strings.ToLower(str)
+}
+
+func (l LowerCase) DefaultMessage() string {
+	return "Must be lowercase"
+}
+
+func (v *Validation) LowerCase(str string) *ValidationResult {
+	return v.check(LowerCase{}, str)
+}
+
+// Requires a string to have no lowercase letters.  Other characters, such as
+// digits and punctuation, are allowed, so the empty string is uppercase.
+type UpperCase struct{}
+
+func (u UpperCase) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && str == strings.ToUpper(str)
+}
+
+func (u UpperCase) DefaultMessage() string {
+	return "Must be uppercase"
+}
+
+func (v *Validation) UpperCase(str string) *ValidationResult {
+	return v.check(UpperCase{}, str)
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[a-zA-Z/!?]`)
+	htmlEntityPattern = regexp.MustCompile(`&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+)
+
+// Requires a string to contain no HTML: no tags (or comments, etc.), and no
+// character references such as &lt; or &#60;.  This is a guard against
+// suspicious input in plain-text fields, not a sanitizer; output must still be
+// escaped.  A "<" that does not begin a tag, as in "a < b", is allowed.
+type NoHTML struct{}
+
+func (n NoHTML) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && !htmlTagPattern.MatchString(str) && !htmlEntityPattern.MatchString(str)
+}
+
+func (n NoHTML) DefaultMessage() string {
+	return "HTML is not allowed"
+}
+
+func (v *Validation) NoHTML(str string) *ValidationResult {
+	return v.check(NoHTML{}, str)
+}
+
+var hexPattern = regexp.MustCompile("^[0-9a-fA-F]+$")
+
+// Requires a string to consist only of hexadecimal digits.  If EvenLength is
+// set, the number of digits must also be even (i.e. whole bytes).
+type Hexadecimal struct {
+	EvenLength bool
+}
+
+func (h Hexadecimal) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok || !hexPattern.MatchString(str) {
+		return false
+	}
+	return !h.EvenLength || len(str)%2 == 0
+}
+
+func (h Hexadecimal) DefaultMessage() string {
+	if h.EvenLength {
+		return "Must be a hexadecimal string with an even number of digits"
+	}
+	return "Must be a hexadecimal string"
+}
+
+func (v *Validation) Hexadecimal(str string) *ValidationResult {
+	return v.check(Hexadecimal{}, str)
+}
+
+// Like Hexadecimal, but also requires an even number of digits, as for an
+// encoded byte string.
+func (v *Validation) HexadecimalBytes(str string) *ValidationResult {
+	return v.check(Hexadecimal{EvenLength: true}, str)
+}
+
+// Requires a string to be base64-encoded: in the standard, padded encoding, or
+// if URLSafe is set, in the unpadded URL-safe encoding.
+type Base64 struct {
+	URLSafe bool
+}
+
+func (b Base64) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok || str == "" {
+		return false
+	}
+	encoding := base64.StdEncoding
+	if b.URLSafe {
+		encoding = base64.RawURLEncoding
+	}
+	_, err := encoding.DecodeString(str)
+	return err == nil
+}
+
+func (b Base64) DefaultMessage() string {
+	return "Must be a base64-encoded string"
+}
+
+func (v *Validation) Base64(str string, urlSafe bool) *ValidationResult {
+	return v.check(Base64{urlSafe}, str)
+}
+
+// Requires a string to be well-formed JSON (of any type: an object, array,
+// string, number, etc.).  The empty string is not valid JSON.
+type JSON struct{}
+
+func (j JSON) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && str != "" && json.Valid([]byte(str))
+}
+
+func (j JSON) DefaultMessage() string {
+	return "Must be valid JSON"
+}
+
+func (v *Validation) JSON(str string) *ValidationResult {
+	return v.check(JSON{}, str)
+}
+
+// Requires a string to be a plausible credit card number: 13 to 19 digits
+// (ignoring spaces and dashes) that pass the Luhn checksum.
+type CreditCard struct{}
+
+func (c CreditCard) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(str)
+	if len(digits) < 13 || len(digits) > 19 || !numericPattern.MatchString(digits) {
+		return false
+	}
+
+	// Double every second digit, starting from the right.
+	sum := 0
+	for i := range digits {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+func (c CreditCard) DefaultMessage() string {
+	return "Must be a valid credit card number"
+}
+
+func (v *Validation) CreditCard(str string) *ValidationResult {
+	return v.check(CreditCard{}, str)
+}
+
+// Requires a string to be an ISBN of the given Version (10 or 13), ignoring
+// spaces and hyphens, with a valid check digit.  Version 0 accepts either.
+type ISBN struct {
+	Version int
+}
+
+func (i ISBN) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(str)
+	switch {
+	case len(digits) == 10 && i.Version != 13:
+		return isbn10(digits)
+	case len(digits) == 13 && i.Version != 10:
+		return isbn13(digits)
+	}
+	return false
+}
+
+// The ISBN-10 check digit (which may be X, for 10) makes the sum of the digits,
+// weighted from 10 down to 1, a multiple of 11.
+func isbn10(digits string) bool {
+	sum := 0
+	for i, c := range digits {
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case (c == 'X' || c == 'x') && i == 9:
+			digit = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+// The ISBN-13 check digit makes the sum of the digits, weighted alternately by
+// 1 and 3, a multiple of 10.
+func isbn13(digits string) bool {
+	if !numericPattern.MatchString(digits) {
+		return false
+	}
+	sum := 0
+	for i := range digits {
+		digit := int(digits[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+func (i ISBN) DefaultMessage() string {
+	return "Must be a valid ISBN"
+}
+
+func (v *Validation) ISBN(str string, version int) *ValidationResult {
+	return v.check(ISBN{version}, str)
+}
+
+var (
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	nanpPattern = regexp.MustCompile(`^(?:\+?1[-. ]?)?(?:\(([2-9][0-9]{2})\)|([2-9][0-9]{2}))[-. ]?([2-9][0-9]{2})[-. ]?([0-9]{4})$`)
+
+	// The phone number formats accepted for each region.
+	// Regions that are not listed only accept E.164 numbers.
+	phoneFormats = map[string]phoneFormat{
+		"US": {nanpPattern, "1"},
+		"CA": {nanpPattern, "1"},
+	}
+)
+
+// A region's phone number format.  The digits captured by the pattern's
+// groups (of which unmatched alternatives capture nothing), following the
+// country code, make up the E.164 number.
+type phoneFormat struct {
+	pattern     *regexp.Regexp
+	countryCode string
+}
+
+// Requires a string to be a phone number, in either the international E.164
+// format (e.g. +14155550132) or a common format for the Region (e.g. "US").
+type Phone struct {
+	Region string
+}
+
+func (p Phone) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	_, ok = NormalizePhone(str, p.Region)
+	return ok
+}
+
+func (p Phone) DefaultMessage() string {
+	return "Must be a valid phone number"
+}
+
+func (v *Validation) Phone(str, region string) *ValidationResult {
+	return v.check(Phone{region}, str)
+}
+
+// Returns the phone number in the E.164 format (e.g. "+14155550132"), and
+// whether it is valid, as for the Phone check.  This allows a number to be
+// stored in a canonical form:
+//
+//   if phone, ok := rev.NormalizePhone(c.Params.Get("phone"), "US"); ok { ... }
+func NormalizePhone(str, region string) (string, bool) {
+	str = strings.TrimSpace(str)
+	if e164Pattern.MatchString(str) {
+		return str, true
+	}
+	format, ok := phoneFormats[strings.ToUpper(region)]
+	if !ok {
+		return "", false
+	}
+	match := format.pattern.FindStringSubmatch(str)
+	if match == nil {
+		return "", false
+	}
+	return "+" + format.countryCode + strings.Join(match[1:], ""), true
+}
+
+var (
+	ukPostalPattern = regexp.MustCompile(`^(?i)[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$`)
+
+	// The postal code formats for each region.
+	// Regions that are not listed accept any non-blank code.
+	postalPatterns = map[string]*regexp.Regexp{
+		"US": regexp.MustCompile(`^[0-9]{5}(?:-[0-9]{4})?$`),
+		"CA": regexp.MustCompile(`^(?i)[ABCEGHJ-NPRSTVXY][0-9][ABCEGHJ-NPRSTV-Z] ?[0-9][ABCEGHJ-NPRSTV-Z][0-9]$`),
+		"GB": ukPostalPattern,
+		"UK": ukPostalPattern,
+	}
+)
+
+// Requires a string to be a postal code in the format of the Region: a 5 or
+// 9 digit ZIP code for "US", or a postcode for "CA" or "GB" (or "UK").  For
+// other regions, any non-blank string is accepted.
+type PostalCode struct {
+	Region string
+}
+
+func (p PostalCode) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	str = strings.TrimSpace(str)
+	if pattern, ok := postalPatterns[strings.ToUpper(p.Region)]; ok {
+		return pattern.MatchString(str)
+	}
+	return str != ""
+}
+
+func (p PostalCode) DefaultMessage() string {
+	return "Must be a valid postal code"
+}
+
+func (v *Validation) PostalCode(str, region string) *ValidationResult {
+	return v.check(PostalCode{region}, str)
+}
+
+// Requires a string to meet a password strength policy.
+// Special characters are those that are not letters, digits, or whitespace.
+type Password struct {
+	MinLen         int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// The policy used by Validation.Password when none is given.
+var DefaultPasswordOptions = Password{
+	MinLen:       8,
+	RequireUpper: true,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+func (p Password) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && p.unmet(str) == ""
+}
+
+// Describes the whole policy, e.g. "Must be at least 8 characters and contain
+// an uppercase letter and a digit".
+func (p Password) DefaultMessage() string {
+	var rules []string
+	if p.MinLen > 0 {
+		rules = append(rules, fmt.Sprintf("be at least %d characters", p.MinLen))
+	}
+	var kinds []string
+	for _, kind := range []struct {
+		required bool
+		name     string
+	}{
+		{p.RequireUpper, "an uppercase letter"},
+		{p.RequireLower, "a lowercase letter"},
+		{p.RequireDigit, "a digit"},
+		{p.RequireSpecial, "a special character"},
+	} {
+		if kind.required {
+			kinds = append(kinds, kind.name)
+		}
+	}
+	if len(kinds) > 0 {
+		rules = append(rules, "contain "+joinList(kinds))
+	}
+	if len(rules) == 0 {
+		return "Must be a string"
+	}
+	return "Must " + strings.Join(rules, " and ")
+}
+
+// Joins items into an English list, e.g. "a, b, and c".
+func joinList(items []string) string {
+	switch len(items) {
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " and " + items[1]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + ", and " + items[len(items)-1]
+}
+
+// Returns a message describing the first requirement that the password fails.
+func (p Password) MessageFor(obj interface{}) string {
+	str, _ := obj.(string)
+	if message := p.unmet(str); message != "" {
+		return message
+	}
+	return p.DefaultMessage()
+}
+
+// Returns a message for the first requirement that str does not meet, or "".
+func (p Password) unmet(str string) string {
+	var upper, lower, digit, special bool
+	for _, r := range str {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			special = true
+		}
+	}
+
+	switch {
+	case utf8.RuneCountInString(str) < p.MinLen:
+		return fmt.Sprintf("Must be at least %d characters", p.MinLen)
+	case p.RequireUpper && !upper:
+		return "Must contain at least one uppercase letter"
+	case p.RequireLower && !lower:
+		return "Must contain at least one lowercase letter"
+	case p.RequireDigit && !digit:
+		return "Must contain at least one digit"
+	case p.RequireSpecial && !special:
+		return "Must contain at least one special character"
+	}
+	return ""
+}
+
+// Check the password against the given policy, or DefaultPasswordOptions if
+// none is given.
+func (v *Validation) Password(str string, opts ...Password) *ValidationResult {
+	policy := DefaultPasswordOptions
+	if len(opts) > 0 {
+		policy = opts[0]
+	}
+	return v.check(policy, str)
+}
+
+var amountPattern = regexp.MustCompile(`^(-?)[0-9]+(?:\.([0-9]+))?$`)
+
+// Requires a string to be a monetary amount (e.g. "10.00") with at most
+// MaxDecimals digits after the decimal point.  A MaxDecimals of 0 means the
+// default of 2; use a negative MaxDecimals to allow whole amounts only.
+// Negative amounts are rejected unless AllowNegative is set.
+type Currency struct {
+	MaxDecimals   int
+	AllowNegative bool
+}
+
+// The options used by Validation.Currency when none are given.
+var DefaultCurrencyOptions = Currency{MaxDecimals: 2}
+
+func (c Currency) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	if !ok {
+		return false
+	}
+	match := amountPattern.FindStringSubmatch(strings.TrimSpace(str))
+	if match == nil {
+		return false
+	}
+	return (c.AllowNegative || match[1] == "") && len(match[2]) <= c.decimals()
+}
+
+// Returns the maximum number of decimal places, applying the default.
+func (c Currency) decimals() int {
+	switch {
+	case c.MaxDecimals == 0:
+		return 2
+	case c.MaxDecimals < 0:
+		return 0
+	}
+	return c.MaxDecimals
+}
+
+func (c Currency) DefaultMessage() string {
+	kind := "a non-negative"
+	if c.AllowNegative {
+		kind = "an"
+	}
+	if c.decimals() == 0 {
+		return fmt.Sprintf("Must be %s whole amount", kind)
+	}
+	return fmt.Sprintf("Must be %s amount with at most %d decimal places", kind, c.decimals())
 }
 
-func (m Match) DefaultMessage() string {
-	return fmt.Sprintln("Must match", m.Regexp)
+// Check the amount with the given options, or DefaultCurrencyOptions if none
+// are given.
+func (v *Validation) Currency(str string, opts ...Currency) *ValidationResult {
+	currency := DefaultCurrencyOptions
+	if len(opts) > 0 {
+		currency = opts[0]
+	}
+	return v.check(currency, str)
 }
 
-func (v *Validation) Match(str string, regex *regexp.Regexp) *ValidationResult {
-	return v.check(Match{regex}, str)
+// Requires a value to be equal (by reflect.DeepEqual) to one of a set of
+// allowed Values.  An empty set allows nothing.
+type In struct {
+	Values []interface{}
+}
+
+func (i In) IsSatisfied(obj interface{}) bool {
+	for _, val := range i.Values {
+		if reflect.DeepEqual(obj, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i In) DefaultMessage() string {
+	return "Must be one of: " + joinValues(i.Values)
+}
+
+func (v *Validation) In(obj interface{}, values ...interface{}) *ValidationResult {
+	return v.check(In{values}, obj)
+}
+
+// Requires a value to be equal to none of a set of disallowed Values, e.g.
+// reserved usernames.  Values are compared as by In, so strings are compared
+// exactly:  "Admin" is not blocked by "admin".  (Use a Normalizer such as
+// strings.ToLower to block every case.)
+type NotIn struct {
+	Values []interface{}
+}
+
+func (n NotIn) IsSatisfied(obj interface{}) bool {
+	return !In{n.Values}.IsSatisfied(obj)
+}
+
+func (n NotIn) DefaultMessage() string {
+	return "This value is reserved"
+}
+
+func (v *Validation) NotIn(obj interface{}, values ...interface{}) *ValidationResult {
+	return v.check(NotIn{values}, obj)
+}
+
+// Requires an int to be one of a set of allowed Values.  An empty set allows
+// nothing.  Use NewIntIn to build the check with a set of the Values, for
+// constant-time lookups; an IntIn made without it scans the Values instead.
+// For example:
+//
+//   var validSizes = rev.NewIntIn(8, 10, 12)
+//
+// The Values must not be changed after the check is made.
+type IntIn struct {
+	Values []int
+
+	set map[int]struct{}
+}
+
+// Returns an IntIn for the given values, with the set for lookups built.
+func NewIntIn(values ...int) IntIn {
+	set := make(map[int]struct{}, len(values))
+	for _, val := range values {
+		set[val] = struct{}{}
+	}
+	return IntIn{Values: values, set: set}
+}
+
+func (i IntIn) IsSatisfied(obj interface{}) bool {
+	n, ok := obj.(int)
+	if !ok {
+		return false
+	}
+	if i.set != nil {
+		_, ok = i.set[n]
+		return ok
+	}
+	for _, val := range i.Values {
+		if val == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (i IntIn) DefaultMessage() string {
+	values := make([]interface{}, len(i.Values))
+	for j, val := range i.Values {
+		values[j] = val
+	}
+	return "Must be one of: " + joinValues(values)
+}
+
+func (v *Validation) IntIn(n int, values ...int) *ValidationResult {
+	return v.check(NewIntIn(values...), n)
+}
+
+// Requires an int to be one of the Valid values of an enumeration stored as
+// ints.  Only an int is accepted: a value of another type fails, even if it is
+// numerically equal to a valid one.
+type EnumInt struct {
+	Valid []int
+}
+
+func (e EnumInt) IsSatisfied(obj interface{}) bool {
+	n, ok := obj.(int)
+	if !ok {
+		return false
+	}
+	for _, valid := range e.Valid {
+		if n == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func (e EnumInt) DefaultMessage() string {
+	return (&IntIn{Values: e.Valid}).DefaultMessage()
+}
+
+func (v *Validation) EnumInt(n int, valid ...int) *ValidationResult {
+	return v.check(EnumInt{valid}, n)
+}
+
+// Requires obj to be one of the valid values of an enumeration, of any type
+// (e.g. ints or strings).  Values are compared as by In, so the type must
+// match too: int64(1) is not a valid value if the valid values are ints.
+func (v *Validation) Enum(obj interface{}, valid ...interface{}) *ValidationResult {
+	return v.check(In{valid}, obj)
+}
+
+// Requires a value to be equal (by reflect.DeepEqual) to another value, for
+// example a password and its confirmation.
+type Equals struct {
+	Other interface{}
+}
+
+func (e Equals) IsSatisfied(obj interface{}) bool {
+	return reflect.DeepEqual(obj, e.Other)
+}
+
+func (e Equals) DefaultMessage() string {
+	return fmt.Sprintln("Must be equal to", e.Other)
+}
+
+func (v *Validation) Equals(obj, other interface{}) *ValidationResult {
+	return v.check(Equals{other}, obj)
+}
+
+// Like Equals, but the message does not reveal the expected Value, so that it
+// may be used for a password's confirmation.
+type Confirmation struct {
+	Value interface{}
+}
+
+func (c Confirmation) IsSatisfied(obj interface{}) bool {
+	return reflect.DeepEqual(obj, c.Value)
+}
+
+func (c Confirmation) DefaultMessage() string {
+	return "Does not match"
+}
+
+// Requires confirm to be equal to value.
+func (v *Validation) Confirmation(value, confirm interface{}) *ValidationResult {
+	return v.check(Confirmation{value}, confirm)
+}
+
+// Requires a string to equal an Expected value known to the server (e.g. a
+// CSRF or CAPTCHA token), comparing them in constant time so that the time
+// taken does not reveal how much of the value was right.  (Only the length of
+// the Expected value may be revealed.)  An empty Expected value matches
+// nothing, so that a missing token is never accepted.
+type SecureEquals struct {
+	Expected string
+}
+
+func (s SecureEquals) IsSatisfied(obj interface{}) bool {
+	str, ok := obj.(string)
+	return ok && s.Expected != "" && subtle.ConstantTimeCompare([]byte(str), []byte(s.Expected)) == 1
+}
+
+func (s SecureEquals) DefaultMessage() string {
+	return "Invalid value"
+}
+
+func (v *Validation) SecureEquals(str, expected string) *ValidationResult {
+	return v.check(SecureEquals{expected}, str)
+}
+
+// Requires every element of a slice or array to satisfy the Check.
+type Each struct {
+	Check Check
+}
+
+func (e Each) IsSatisfied(obj interface{}) bool {
+	val := reflect.ValueOf(obj)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return false
+	}
+	for i := 0; i < val.Len(); i++ {
+		if !e.Check.IsSatisfied(val.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e Each) DefaultMessage() string {
+	return "Each element: " + e.Check.DefaultMessage()
+}
+
+// Check each element of the slice or array separately, recording an error for
+// each one that fails (or only the first, if StopOnFirst is set).  Setting the
+// result's Key keys each error by its element's index:
+//
+//   v.Each(emails, rev.Email{}).Key("emails") // e.g. "emails[1]"
+//
+// A value that is not a slice or array fails with a single error.  As with
+// Check, if StopOnFirst is set and the context already has errors, nothing is
+// checked and the returned result is not Ok (and has no Error).
+func (v *Validation) Each(obj interface{}, check Check) *ValidationResult {
+	if v.StopOnFirst && v.HasErrors() {
+		return &ValidationResult{Ok: false}
+	}
+
+	val := reflect.ValueOf(obj)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return v.check(Each{check}, obj)
+	}
+
+	result := &ValidationResult{Ok: true}
+	for i := 0; i < val.Len(); i++ {
+		elemResult := v.check(check, val.Index(i).Interface())
+		if elemResult.Ok {
+			continue
+		}
+		if result.Ok {
+			result.Ok, result.Error = false, elemResult.Error
+		}
+		result.elements = append(result.elements, elementError{elemResult.Error, i})
+		if v.StopOnFirst {
+			break
+		}
+	}
+	return result
+}
+
+// Requires a slice or array to contain no duplicate elements.
+type Unique struct{}
+
+func (u Unique) IsSatisfied(obj interface{}) bool {
+	val := reflect.ValueOf(obj)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return false
+	}
+
+	// Use a map if the elements can be keys, otherwise compare every pair.
+	if elemType := val.Type().Elem(); elemType.Kind() != reflect.Interface && elemType.Comparable() {
+		seen := make(map[interface{}]bool, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			elem := val.Index(i).Interface()
+			if seen[elem] {
+				return false
+			}
+			seen[elem] = true
+		}
+		return true
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		for j := 0; j < i; j++ {
+			if reflect.DeepEqual(val.Index(i).Interface(), val.Index(j).Interface()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (u Unique) DefaultMessage() string {
+	return "Must not contain duplicates"
+}
+
+func (v *Validation) Unique(obj interface{}) *ValidationResult {
+	return v.check(Unique{}, obj)
+}
+
+// Inverts another Check.  For example, Not{In{reserved}} is a blocklist, and
+// Not{Required{}} requires a value to be empty.
+type Not struct {
+	Check Check
+}
+
+func (n Not) IsSatisfied(obj interface{}) bool {
+	return !n.Check.IsSatisfied(obj)
+}
+
+func (n Not) DefaultMessage() string {
+	return "Must not: " + n.Check.DefaultMessage()
+}
+
+func (v *Validation) Not(obj interface{}, chk Check) *ValidationResult {
+	return v.check(Not{chk}, obj)
+}
+
+// Returns the values formatted as a comma-separated list.
+func joinValues(values []interface{}) string {
+	strs := make([]string, len(values))
+	for i, val := range values {
+		strs[i] = fmt.Sprint(val)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Returns obj as a string, if it is a string, []byte, or fmt.Stringer.
+func toString(obj interface{}) (string, bool) {
+	switch val := obj.(type) {
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	case fmt.Stringer:
+		return val.String(), true
+	}
+	return "", false
+}
+
+// Converts any of the int, uint, and float types to a float64, so that numbers
+// of different widths may be compared.  Returns false if obj is not a number.
+func toFloat64(obj interface{}) (float64, bool) {
+	val := reflect.ValueOf(obj)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+// Returns the string with each run of whitespace replaced by a single space,
+// and leading and trailing whitespace removed.  e.g. " a \t b " => "a b"
+func NormalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Returns a Check that is satisfied exactly when c is, but whose message is
+// msg, so that reusable sets of checks may carry their own messages:
+//
+//   adult := rev.WithMessage(rev.Min{18}, "Must be 18+")
+func WithMessage(c Check, msg string) Check {
+	return messaged{c, msg}
+}
+
+type messaged struct {
+	Check
+	message string
+}
+
+func (m messaged) DefaultMessage() string {
+	return m.message
+}
+
+// Applications may override the DefaultMessage of any Check by adding it here,
+// keyed by the name of the Check's type (e.g. "Required" or "Min").
+// Placeholders {0}, {1}, ... are replaced by the Check's fields, in order.
+// For example:
+//
+//   rev.DefaultMessages["Range"] = "Must be between {0} and {1}"
+//
+// The map may be changed directly only during initialization.  Use
+// SetDefaultMessage to change a message once requests are being served.
+var DefaultMessages = map[string]string{}
+
+// Guards DefaultMessages.
+var defaultMessagesLock sync.RWMutex
+
+// Set the override for the DefaultMessage of the named Check type, as in
+// DefaultMessages.  An empty message removes the override.  It is safe to call
+// while other goroutines are validating.
+func SetDefaultMessage(checkName, message string) {
+	defaultMessagesLock.Lock()
+	defer defaultMessagesLock.Unlock()
+	if message == "" {
+		delete(DefaultMessages, checkName)
+	} else {
+		DefaultMessages[checkName] = message
+	}
+}
+
+// Checks whose message depends on the value that failed (e.g. to report which
+// of several requirements was not met) may implement this in addition to Check.
+type valueMessager interface {
+	MessageFor(obj interface{}) string
+}
+
+// Returns the message for a failed Check: the override from messages or
+// DefaultMessages, if there is one, or else the Check's own message.
+func checkMessage(chk Check, obj interface{}, messages map[string]string) string {
+	val := reflect.Indirect(reflect.ValueOf(chk))
+	message, ok := messages[val.Type().Name()]
+	if !ok {
+		defaultMessagesLock.RLock()
+		message, ok = DefaultMessages[val.Type().Name()]
+		defaultMessagesLock.RUnlock()
+	}
+	if !ok {
+		if messager, ok := chk.(valueMessager); ok {
+			return messager.MessageFor(obj)
+		}
+		return chk.DefaultMessage()
+	}
+	if val.Kind() == reflect.Struct {
+		for i := 0; i < val.NumField(); i++ {
+			message = strings.Replace(message, fmt.Sprintf("{%d}", i), fmt.Sprint(val.Field(i)), -1)
+		}
+	}
+	return message
+}
+
+// Returns the error code for a failed Check: the name of its type in
+// snake_case, e.g. "min_size" for MinSize, or "ip_addr" for IPAddr.  Checks
+// from WithMessage have the code of the Check they wrap.
+func checkCode(chk Check) string {
+	if m, ok := chk.(messaged); ok {
+		return checkCode(m.Check)
+	}
+	name := reflect.Indirect(reflect.ValueOf(chk)).Type().Name()
+	var code []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				code = append(code, '_')
+			}
+		}
+		code = append(code, unicode.ToLower(r))
+	}
+	return string(code)
 }
 
 func (v *Validation) check(chk Check, obj interface{}) *ValidationResult {
+	if v.StopOnFirst && v.HasErrors() {
+		return &ValidationResult{Ok: false}
+	}
+	if str, ok := obj.(string); ok {
+		if v.Normalizer != nil {
+			str = v.Normalizer(str)
+		}
+		if v.CollapseSpace {
+			str = NormalizeSpace(str)
+		}
+		obj = str
+	}
 	if chk.IsSatisfied(obj) {
 		return &ValidationResult{Ok: true}
 	}
 
 	// Add the error to the validation context.
+	message := checkMessage(chk, obj, v.Messages)
+	if v.Translator != nil {
+		message = v.Translator(message)
+	}
 	err := &ValidationError{
-		Message: chk.DefaultMessage(),
+		Message: message,
+		Code:    checkCode(chk),
+		Value:   obj,
 	}
 	v.Errors = append(v.Errors, err)
 
@@ -323,7 +2398,14 @@ func (v *Validation) check(chk Check, obj interface{}) *ValidationResult {
 
 // Apply a group of Checks to a field, in order, and return the ValidationResult
 // from the first Check that fails, or the last one that succeeds.
+//
+// If StopOnFirst is set and the context already has errors, no Checks are run
+// and the returned result is not Ok (and has no Error).
 func (v *Validation) Check(obj interface{}, checks ...Check) *ValidationResult {
+	if v.StopOnFirst && v.HasErrors() {
+		return &ValidationResult{Ok: false}
+	}
+
 	var result *ValidationResult
 	for _, check := range checks {
 		result = v.check(check, obj)
@@ -333,3 +2415,289 @@ func (v *Validation) Check(obj interface{}, checks ...Check) *ValidationResult {
 	}
 	return result
 }
+
+// Like Check, but keys the error (if any) with the given key.
+// e.g. v.CheckKey("username", username, rev.Required{}, rev.MinSize{4})
+func (v *Validation) CheckKey(key string, obj interface{}, checks ...Check) *ValidationResult {
+	result := v.Check(obj, checks...)
+	if result == nil {
+		return &ValidationResult{Ok: true}
+	}
+	return result.Key(key)
+}
+
+// Apply the Checks for each key in rules to the value with the same key in
+// values, keying any error by the key.  A key without a value is checked
+// against nil, so that Required fails.  For example:
+//
+//   v.Validate(map[string][]rev.Check{
+//   	"email": {rev.Required{}, rev.Email{}},
+//   	"age":   {rev.Min{13}},
+//   }, map[string]interface{}{"email": email, "age": age})
+//
+// The keys are validated in sorted order, so that the order of the errors is
+// predictable.
+func (v *Validation) Validate(rules map[string][]Check, values map[string]interface{}) {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if checks := rules[key]; len(checks) > 0 {
+			v.Check(values[key], checks...).Key(key)
+		}
+	}
+}
+
+// A FieldValidation applies one key to the errors from all of its checks, so
+// that the checks on a field may be chained without repeating the key:
+//
+//   v.Field("age").Required(age).Min(age, 18)
+//
+// The checks are run through Validation.Check, so StopOnFirst is honored.
+type FieldValidation struct {
+	v      *Validation
+	key    string
+	result *ValidationResult
+}
+
+// Returns a builder for checks whose errors are keyed by key.
+func (v *Validation) Field(key string) *FieldValidation {
+	return &FieldValidation{v: v, key: key}
+}
+
+// Apply the checks to obj, keying the error (if any) with the field's key.
+func (f *FieldValidation) Check(obj interface{}, checks ...Check) *FieldValidation {
+	if len(checks) > 0 {
+		f.result = f.v.Check(obj, checks...).Key(f.key)
+	}
+	return f
+}
+
+// Set the message of the error from the preceding check, if it failed.
+func (f *FieldValidation) Message(message string) *FieldValidation {
+	if f.result != nil {
+		f.result.Message(message)
+	}
+	return f
+}
+
+func (f *FieldValidation) Required(obj interface{}) *FieldValidation {
+	return f.Check(obj, Required{})
+}
+
+func (f *FieldValidation) Min(n interface{}, min int) *FieldValidation {
+	return f.Check(n, Min{min})
+}
+
+func (f *FieldValidation) Max(n interface{}, max int) *FieldValidation {
+	return f.Check(n, Max{max})
+}
+
+func (f *FieldValidation) Range(n interface{}, min, max int) *FieldValidation {
+	return f.Check(n, Range{min, max})
+}
+
+func (f *FieldValidation) MinSize(obj interface{}, min int) *FieldValidation {
+	return f.Check(obj, MinSize{min})
+}
+
+func (f *FieldValidation) MaxSize(obj interface{}, max int) *FieldValidation {
+	return f.Check(obj, MaxSize{max})
+}
+
+func (f *FieldValidation) Length(obj interface{}, n int) *FieldValidation {
+	return f.Check(obj, Length{n})
+}
+
+func (f *FieldValidation) Match(str string, regex *regexp.Regexp) *FieldValidation {
+	return f.Check(str, Match{regex})
+}
+
+func (f *FieldValidation) Email(str string) *FieldValidation {
+	return f.Check(str, Email{})
+}
+
+// Validate the exported fields of a struct (or pointer to struct) according to
+// the rules in their "valid" tags.  For example:
+//
+//   type User struct {
+//   	Username string `valid:"required,minSize=4,maxSize=15"`
+//   	Age      int    `valid:"min=13" json:"age"`
+//   }
+//
+// The supported rules are required, min=N, max=N, minSize=N, maxSize=N, and
+// match=REGEX.  Since a pattern may itself contain commas, match must be the
+// last rule in the tag.
+//
+// Errors are keyed by the field's json name, if it has one, or else by the
+// field name.  An unknown rule causes a panic, so that typos are caught the
+// first time the struct is validated.  A match pattern that does not compile
+// is reported as an error on its field instead.  Tags are parsed once and
+// cached, so patterns are not recompiled on every call.
+//
+// Fields holding structs (or pointers to, or slices of, structs) are validated
+// too, with errors keyed by their path, e.g. "items[0].quantity".
+//
+// Every field is validated, unless StopOnFirst is set, in which case the
+// remaining fields are skipped after the first failure.
+func (v *Validation) Struct(obj interface{}) {
+	v.StructGroup(obj, "")
+}
+
+// Like Struct, but only validates the fields that belong to the given group,
+// plus those that do not belong to any group.  Fields declare their groups
+// with a comma-separated "groups" tag.  For example:
+//
+//   Password string `valid:"required,minSize=5" groups:"create"`
+//
+// is only required by StructGroup(user, "create").  An empty group validates
+// every field, regardless of its groups.
+func (v *Validation) StructGroup(obj interface{}, group string) {
+	if reflect.Indirect(reflect.ValueOf(obj)).Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Validation.Struct expects a struct, got %T", obj))
+	}
+	v.validateNested(reflect.ValueOf(obj), "", group, make(map[uintptr]bool))
+}
+
+// Validate the fields of any structs within val (which may be a struct, a
+// pointer to one, or a slice or array of them), keying errors by their path
+// from the top-level struct.  Pointers that are already being validated are
+// skipped, so that cyclic data structures terminate.
+func (v *Validation) validateNested(val reflect.Value, key, group string, visiting map[uintptr]bool) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() || visiting[val.Pointer()] {
+			return
+		}
+		visiting[val.Pointer()] = true
+		defer delete(visiting, val.Pointer())
+		v.validateNested(val.Elem(), key, group, visiting)
+
+	case reflect.Interface:
+		if !val.IsNil() {
+			v.validateNested(val.Elem(), key, group, visiting)
+		}
+
+	case reflect.Slice, reflect.Array:
+		switch val.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array:
+			for i := 0; i < val.Len(); i++ {
+				v.validateNested(val.Index(i), fmt.Sprintf("%s[%d]", key, i), group, visiting)
+			}
+		}
+
+	case reflect.Struct:
+		prefix := ""
+		if key != "" {
+			prefix = key + "."
+		}
+		v.validateFields(val, prefix, group, visiting)
+	}
+}
+
+func (v *Validation) validateFields(val reflect.Value, prefix, group string, visiting map[uintptr]bool) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if v.StopOnFirst && v.HasErrors() {
+			return
+		}
+
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if groups := field.Tag.Get("groups"); group != "" && groups != "" &&
+			!ContainsString(strings.Split(groups, ","), group) {
+			continue
+		}
+		key := prefix + fieldKey(field)
+		if checks, err := parseValidTag(field.Tag.Get("valid")); err != nil {
+			v.AddError(key, err.Error())
+		} else if len(checks) > 0 {
+			v.Check(val.Field(i).Interface(), checks...).Key(key)
+		}
+		v.validateNested(val.Field(i), key, group, visiting)
+	}
+}
+
+// Returns the key used for errors on the given struct field.
+func fieldKey(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// The result of parsing a "valid" struct tag.
+type parsedTag struct {
+	checks []Check
+	err    error
+}
+
+// Parsed "valid" tags, keyed by the tag, so that match patterns are compiled
+// once rather than every time a struct is validated.
+var parsedTags sync.Map
+
+// Translate a "valid" struct tag into the Checks that it describes.  Returns
+// an error if a match pattern does not compile.
+func parseValidTag(tag string) ([]Check, error) {
+	if parsed, ok := parsedTags.Load(tag); ok {
+		return parsed.(*parsedTag).checks, parsed.(*parsedTag).err
+	}
+	checks, err := compileValidTag(tag)
+	parsedTags.Store(tag, &parsedTag{checks, err})
+	return checks, err
+}
+
+func compileValidTag(tag string) ([]Check, error) {
+	checks := []Check{}
+	for tag != "" {
+		var rule string
+		tag = strings.TrimLeft(tag, " ")
+		if comma := strings.Index(tag, ","); comma == -1 || strings.HasPrefix(tag, "match=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag = tag[:comma], tag[comma+1:]
+		}
+
+		name, arg := strings.TrimSpace(rule), ""
+		if eq := strings.Index(name, "="); eq != -1 {
+			name, arg = name[:eq], name[eq+1:]
+		}
+
+		switch name {
+		case "":
+			continue
+		case "required":
+			checks = append(checks, Required{})
+		case "min":
+			checks = append(checks, Min{tagInt(rule, arg)})
+		case "max":
+			checks = append(checks, Max{tagInt(rule, arg)})
+		case "minSize":
+			checks = append(checks, MinSize{tagInt(rule, arg)})
+		case "maxSize":
+			checks = append(checks, MaxSize{tagInt(rule, arg)})
+		case "match":
+			regex, err := regexp.Compile(arg)
+			if err != nil {
+				return nil, fmt.Errorf("Bad pattern in validation rule %q: %s", rule, err)
+			}
+			checks = append(checks, Match{regex})
+		default:
+			panic(fmt.Sprintf("Unknown validation rule: %q", rule))
+		}
+	}
+	return checks, nil
+}
+
+func tagInt(rule, arg string) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Sprintf("Validation rule %q requires an integer argument", rule))
+	}
+	return n
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // Need to test dates.
@@ -75,6 +79,34 @@ func TestRequiredAndMissing(t *testing.T) {
 	}
 }
 
+// Tests the same cases as above, using RequiredParam.
+func TestRequiredParam(t *testing.T) {
+	v := &Validation{}
+	for _, name := range []string{"name", "age", "money", "negative"} {
+		v.RequiredParam(params, name, stringType)
+	}
+	v.RequiredParam(params, "age", intType)
+	v.RequiredParam(params, "boolean", boolType)
+	if v.HasErrors() {
+		t.Errorf("Validation has errors!\n%v\n", v.ErrorMap())
+	}
+
+	v = &Validation{}
+	v.RequiredParam(params, "blank_str", stringType)
+	v.RequiredParam(params, "fake", stringType)
+	errorMap := v.ErrorMap()
+	if len(errorMap) != 2 || errorMap["blank_str"] == nil || errorMap["fake"] == nil {
+		t.Errorf("Validation should have two errors!\n%v\n", errorMap)
+	}
+}
+
+func TestRequiredMap(t *testing.T) {
+	var nilMap map[string]string
+	expectSatisfied(t, Required{}, nilMap, false)
+	expectSatisfied(t, Required{}, map[string]string{}, false)
+	expectSatisfied(t, Required{}, map[string]string{"a": "b"}, true)
+}
+
 func requiredString(v *Validation, paramName string) {
 	v.Required(Bind(params, paramName, stringType).Interface().(string)).Key(paramName)
 }
@@ -86,3 +118,1650 @@ func requiredBool(v *Validation, paramName string) {
 func requiredInt(v *Validation, paramName string) {
 	v.Required(Bind(params, paramName, intType).Interface().(int)).Key(paramName)
 }
+
+func TestErrorLookup(t *testing.T) {
+	v := &Validation{}
+	v.Required("").Key("name")
+	v.MinSize("", 3).Key("name")
+	v.Min(1, 5).Key("age")
+
+	if !v.HasError("name") || !v.HasError("age") {
+		t.Errorf("Expected errors for name and age, got %v", v.ErrorMap())
+	}
+	if v.Error("name") != v.Errors[0] {
+		t.Errorf("Expected the first error for name, got %v", v.Error("name"))
+	}
+	if v.HasError("fake") || v.Error("fake") != nil {
+		t.Errorf("Expected no error for fake, got %v", v.Error("fake"))
+	}
+}
+
+func TestErrorsByKey(t *testing.T) {
+	v := &Validation{}
+	v.Check("", Required{}).Key("name")
+	v.MinSize("", 3).Key("name")
+	v.Min(1, 5).Key("age")
+
+	errors := v.ErrorsByKey()
+	if len(errors["name"]) != 2 || len(errors["age"]) != 1 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+	eq(t, "name[0]", errors["name"][0].Message, Required{}.DefaultMessage())
+	eq(t, "name[1]", errors["name"][1].Message, MinSize{3}.DefaultMessage())
+}
+
+func TestResult(t *testing.T) {
+	v := &Validation{}
+	eq(t, "empty HasErrors", v.Result().HasErrors, false)
+	eq(t, "empty ByKey", len(v.Result().ByKey), 0)
+
+	v.Required("").Key("name")
+	v.MinSize("", 3).Key("name")
+	v.Min(1, 5).Key("age")
+
+	summary := v.Result()
+	eq(t, "HasErrors", summary.HasErrors, true)
+	eq(t, "Errors", len(summary.Errors), 3)
+	eq(t, "ByKey", len(summary.ByKey), 2)
+	eq(t, "ByKey first wins", summary.ByKey["name"].Message, Required{}.DefaultMessage())
+
+	v.Clear()
+	eq(t, "snapshot after Clear", len(summary.Errors), 3)
+	eq(t, "Result after Clear", v.Result().HasErrors, false)
+}
+
+func TestValidationString(t *testing.T) {
+	v := &Validation{}
+	eq(t, "empty String", v.String(), "")
+	eq(t, "empty Len", v.Len(), 0)
+
+	v.Required("").Key("name")
+	v.Min(1, 5).Key("age")
+	eq(t, "String", fmt.Sprint(v), "name: Required\nage: Minimum is 5")
+	eq(t, "Len", v.Len(), 2)
+}
+
+func TestClearKeyAndKeepKeys(t *testing.T) {
+	newValidation := func() *Validation {
+		v := &Validation{}
+		v.Required("").Key("name")
+		v.Min(1, 5).Key("age")
+		v.MinSize("", 3).Key("name")
+		v.Email("rob").Key("email")
+		return v
+	}
+
+	v := newValidation()
+	v.ClearKey("name")
+	eq(t, "ClearKey", v.String(), "age: Minimum is 5\nemail: "+Email{}.DefaultMessage())
+	v.ClearKey("missing")
+	eq(t, "ClearKey (missing)", v.Len(), 2)
+
+	v = newValidation()
+	v.KeepKeys("name", "email")
+	eq(t, "KeepKeys", v.String(),
+		"name: Required\nname: "+strings.TrimRight(MinSize{3}.DefaultMessage(), "\n")+"\nemail: "+Email{}.DefaultMessage())
+
+	v.KeepKeys()
+	eq(t, "KeepKeys (none)", v.HasErrors(), false)
+}
+
+func TestAddError(t *testing.T) {
+	v := &Validation{}
+	v.Required("").Key("email")
+	result := v.AddError("username", "Username is already taken")
+	if result.Ok || result.Error == nil {
+		t.Errorf("AddError should return a failed result, got %v", result)
+	}
+
+	errorMap := v.ErrorMap()
+	if !v.HasErrors() || len(errorMap) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errorMap)
+	}
+	eq(t, "email", errorMap["email"].Message, Required{}.DefaultMessage())
+	eq(t, "username", errorMap["username"].Message, "Username is already taken")
+}
+
+func TestNormalizer(t *testing.T) {
+	v := &Validation{}
+	if !v.Required("  ").Ok {
+		t.Errorf("Spaces should be present without a Normalizer")
+	}
+	if v.RequiredTrimmed("  ").Ok || !v.RequiredTrimmed(" x ").Ok {
+		t.Errorf("RequiredTrimmed should ignore surrounding spaces")
+	}
+
+	v = &Validation{Normalizer: strings.TrimSpace}
+	if v.Required("  ").Ok {
+		t.Errorf("Spaces should be empty after normalizing")
+	}
+	if !v.MaxSize("  abc  ", 3).Ok || !v.Min(5, 1).Ok {
+		t.Errorf("Validation has errors!\n%v\n", v.ErrorMap())
+	}
+}
+
+func TestNormalizeSpace(t *testing.T) {
+	eq(t, "runs", NormalizeSpace("a   b"), "a b")
+	eq(t, "mixed", NormalizeSpace(" a \t\n b  c "), "a b c")
+	eq(t, "blank", NormalizeSpace("   "), "")
+	eq(t, "unchanged", NormalizeSpace("a b"), "a b")
+
+	v := &Validation{}
+	if v.MaxSize("a      b", 3).Ok {
+		t.Errorf("Spaces should count without CollapseSpace")
+	}
+	v = &Validation{CollapseSpace: true}
+	if !v.MaxSize("a      b", 3).Ok || v.Required("   ").Ok {
+		t.Errorf("Spaces should be collapsed with CollapseSpace")
+	}
+
+	v = &Validation{Normalizer: strings.ToUpper, CollapseSpace: true}
+	if !v.Equals("a   b", "A B").Ok {
+		t.Errorf("CollapseSpace should apply after the Normalizer")
+	}
+}
+
+func TestRequiredPointer(t *testing.T) {
+	empty, x := "", "x"
+	expectSatisfied(t, Required{}, (*string)(nil), false)
+	expectSatisfied(t, Required{}, &empty, false)
+	expectSatisfied(t, Required{}, &x, true)
+	expectSatisfied(t, Required{}, (*int)(nil), false)
+	expectSatisfied(t, Required{}, new(int), true)
+	expectSatisfied(t, Required{}, (*Validation)(nil), false)
+
+	var err error
+	expectSatisfied(t, Required{}, err, false)
+	pErr := &err
+	expectSatisfied(t, Required{}, pErr, false)
+}
+
+func TestNonBlank(t *testing.T) {
+	expectSatisfied(t, NonBlank{}, "", false)
+	expectSatisfied(t, NonBlank{}, "   ", false)
+	expectSatisfied(t, NonBlank{}, "\t\n", false)
+	expectSatisfied(t, NonBlank{}, " x ", true)
+	expectSatisfied(t, NonBlank{}, 5, true)
+	expectSatisfied(t, NonBlank{}, nil, false)
+
+	// Required is unchanged: whitespace counts as a value.
+	expectSatisfied(t, Required{}, "   ", true)
+}
+
+func TestAccepted(t *testing.T) {
+	expectSatisfied(t, Accepted{}, true, true)
+	expectSatisfied(t, Accepted{}, false, false)
+	expectSatisfied(t, Accepted{}, "on", true)
+	expectSatisfied(t, Accepted{}, "Yes", true)
+	expectSatisfied(t, Accepted{}, "1", true)
+	expectSatisfied(t, Accepted{}, "", false)
+	expectSatisfied(t, Accepted{}, "off", false)
+	expectSatisfied(t, Accepted{}, 1, false)
+
+	v := &Validation{}
+	v.Accepted(false).Key("terms")
+	eq(t, "Accepted message", v.Error("terms").Message, "Must be accepted")
+}
+
+func TestRequiredIf(t *testing.T) {
+	v := &Validation{}
+	if v.RequiredIf("", true).Ok || !v.HasErrors() {
+		t.Errorf("Expected an error when required and empty")
+	}
+
+	v = &Validation{}
+	if !v.RequiredIf("because", true).Ok || !v.RequiredIf("", false).Ok || v.HasErrors() {
+		t.Errorf("Validation has errors!\n%v\n", v.ErrorMap())
+	}
+}
+
+func TestRequiredWith(t *testing.T) {
+	for _, test := range []struct {
+		obj          string
+		otherPresent bool
+		withOk       bool
+		withoutOk    bool
+	}{
+		{"", true, false, true},
+		{"", false, true, false},
+		{"x", true, true, true},
+		{"x", false, true, true},
+	} {
+		v := &Validation{}
+		eq(t, fmt.Sprintf("RequiredWith(%q, %v)", test.obj, test.otherPresent),
+			v.RequiredWith(test.obj, test.otherPresent).Ok, test.withOk)
+		eq(t, fmt.Sprintf("RequiredWithout(%q, %v)", test.obj, test.otherPresent),
+			v.RequiredWithout(test.obj, test.otherPresent).Ok, test.withoutOk)
+	}
+
+	// Either phone or email is required.
+	v := &Validation{}
+	phone, email := "", ""
+	v.RequiredWithout(phone, email != "").Key("phone")
+	v.RequiredWithout(email, phone != "").Key("email")
+	eq(t, "neither", len(v.Errors), 2)
+
+	v = &Validation{}
+	email = "rob@example.com"
+	v.RequiredWithout(phone, email != "").Key("phone")
+	v.RequiredWithout(email, phone != "").Key("email")
+	eq(t, "email only", v.HasErrors(), false)
+}
+
+func TestMessagef(t *testing.T) {
+	v := &Validation{}
+	v.MaxSize("toolong", 5).Key("x").Messagef("too long: max %d", 5)
+	v.MaxSize("ok", 5).Key("y").Messagef("too long: max %d", 5)
+
+	if len(v.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %v", v.ErrorMap())
+	}
+	eq(t, "x", v.Error("x").Message, "too long: max 5")
+}
+
+func TestClear(t *testing.T) {
+	v := &Validation{}
+	v.Required("").Key("name")
+	v.Keep()
+	v.Clear()
+	if v.HasErrors() || v.keep {
+		t.Errorf("Clear did not reset the context: %#v", v)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	step1, step2 := &Validation{}, &Validation{}
+	step1.Required("").Key("name")
+	step2.Required("").Key("email")
+	step2.MinSize("", 3).Key("name")
+
+	step1.Merge(step2)
+	step1.Merge(nil)
+	if len(step1.Errors) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", step1.Errors)
+	}
+	eq(t, "email", step1.Errors[1].Key, "email")
+	eq(t, "name (first wins)", step1.Error("name").Message, Required{}.DefaultMessage())
+}
+
+func TestCopy(t *testing.T) {
+	original := &Validation{}
+	original.Required("").Key("name")
+
+	c := original.Copy()
+	c.Errors[0].Message = "changed"
+	c.Required("").Key("email")
+
+	if len(original.Errors) != 1 || original.Errors[0].Message != (Required{}).DefaultMessage() {
+		t.Errorf("Original was modified: %v", original.Errors)
+	}
+	if len(c.Errors) != 2 {
+		t.Errorf("Expected 2 errors in the copy, got %v", c.Errors)
+	}
+}
+
+func TestErrorsJSON(t *testing.T) {
+	v := &Validation{}
+	v.Required("").Key("email")
+	v.AddError("username", "Already taken")
+
+	b, err := v.ErrorsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq(t, "JSON", string(b),
+		`[{"message":"Required","key":"email","code":"required"},{"message":"Already taken","key":"username"}]`)
+
+	for _, v := range []*Validation{nil, {}} {
+		b, _ = v.ErrorsJSON()
+		eq(t, "empty JSON", string(b), "[]")
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	v := &Validation{}
+	v.Required("").Key("name")
+	v.MinSize("ab", 3).Key("short")
+	v.Email("rob").Key("email")
+	v.IPAddr("x").Key("ip")
+	v.Check("x", URL{}).Key("url")
+	v.Min(1, 5).Key("age").Code("too_young")
+	v.AddError("username", "Already taken").Code("taken")
+	v.AddError("other", "Other")
+
+	for key, code := range map[string]string{
+		"name":     "required",
+		"short":    "min_size",
+		"email":    "email",
+		"ip":       "ip_addr",
+		"url":      "url",
+		"age":      "too_young",
+		"username": "taken",
+		"other":    "",
+	} {
+		eq(t, key+" code", v.Error(key).Code, code)
+	}
+	eq(t, "message unchanged", v.Error("name").Message, "Required")
+	eq(t, "AlphaNumeric code", checkCode(AlphaNumeric{}), "alpha_numeric")
+	eq(t, "IntIn code", checkCode(IntIn{}), "int_in")
+}
+
+func TestErrorValue(t *testing.T) {
+	v := &Validation{Normalizer: strings.TrimSpace}
+	v.MinSize(" ab ", 3).Key("name")
+	v.Min(2, 5).Key("age")
+	v.MinSize("abc", 3).Key("ok")
+	v.AddError("username", "Already taken")
+
+	eq(t, "name value", v.Error("name").Value, "ab")
+	eq(t, "age value", v.Error("age").Value, 2)
+	eq(t, "AddError value", v.Error("username").Value, nil)
+
+	b, _ := v.ErrorsJSON()
+	if strings.Contains(string(b), "value") {
+		t.Errorf("Expected the value to be omitted from JSON, got %s", b)
+	}
+}
+
+// Tests that the numeric validators accept ints and floats of any width.
+func TestNumericWidths(t *testing.T) {
+	tests := []struct {
+		check    Check
+		obj      interface{}
+		expected bool
+	}{
+		{Range{0, 100}, 50, true},
+		{Range{0, 100}, int64(100), true},
+		{Range{0, 100}, 50.0, true},
+		{Range{0, 100}, 100.5, false},
+		{Range{0, 100}, "50", false},
+		{Min{10}, 10, true},
+		{Min{10}, int64(9), false},
+		{Min{10}, 10.5, true},
+		{Min{10}, nil, false},
+		{Max{10}, 10, true},
+		{Max{10}, int64(11), false},
+		{Max{10}, 9.99, true},
+		{Max{10}, "9", false},
+	}
+
+	for _, test := range tests {
+		expectSatisfied(t, test.check, test.obj, test.expected)
+	}
+}
+
+func TestNumericStrings(t *testing.T) {
+	expectSatisfied(t, MinString{18}, "20", true)
+	expectSatisfied(t, MinString{18}, " 18 ", true)
+	expectSatisfied(t, MinString{18}, "17.5", false)
+	expectSatisfied(t, MinString{0}, "abc", false)
+	expectSatisfied(t, MinString{0}, "", false)
+	expectSatisfied(t, MinString{0}, "0x10", false)
+	expectSatisfied(t, MinString{0}, "Inf", false)
+	expectSatisfied(t, MinString{0}, 20, false)
+	expectSatisfied(t, MaxString{10}, "-2.5e1", true)
+	expectSatisfied(t, MaxString{10}, "1,000", false)
+	expectSatisfied(t, RangeString{1, 5}, "+3", true)
+	expectSatisfied(t, RangeString{1, 5}, "6", false)
+
+	// The plain numeric checks still reject strings.
+	expectSatisfied(t, Min{18}, "20", false)
+
+	v := &Validation{}
+	v.MinString("abc", 0).Key("age")
+	eq(t, "abc fails MinString{0}", v.HasError("age"), true)
+	eq(t, "MinString message", MinString{18}.DefaultMessage(), Min{18}.DefaultMessage())
+}
+
+func TestNonZero(t *testing.T) {
+	var nilInt *int
+	one := 1
+	expectSatisfied(t, NonZero{}, 0, false)
+	expectSatisfied(t, NonZero{}, 0.0, false)
+	expectSatisfied(t, NonZero{}, "", false)
+	expectSatisfied(t, NonZero{}, nil, false)
+	expectSatisfied(t, NonZero{}, nilInt, false)
+	expectSatisfied(t, NonZero{}, A{}, false)
+	expectSatisfied(t, NonZero{}, map[string]int{}, false)
+	expectSatisfied(t, NonZero{}, []int{}, false)
+
+	expectSatisfied(t, NonZero{}, 5, true)
+	expectSatisfied(t, NonZero{}, "x", true)
+	expectSatisfied(t, NonZero{}, &one, true)
+	expectSatisfied(t, NonZero{}, A{Id: 1}, true)
+	expectSatisfied(t, NonZero{}, map[string]int{"a": 0}, true)
+}
+
+func TestFloatRange(t *testing.T) {
+	probability := FloatRange{0.0, 1.0}
+	expectSatisfied(t, probability, 0.0, true)
+	expectSatisfied(t, probability, 1.0, true)
+	expectSatisfied(t, probability, 0.5, true)
+	expectSatisfied(t, probability, -0.0001, false)
+	expectSatisfied(t, probability, 1.0001, false)
+	expectSatisfied(t, probability, 1, true)
+	expectSatisfied(t, probability, 2, false)
+	expectSatisfied(t, probability, "0.5", false)
+	eq(t, "FloatRange message", probability.DefaultMessage(), "Valid range is 0 to 1, inclusive.")
+}
+
+func TestGreaterAndLessThan(t *testing.T) {
+	expectSatisfied(t, GreaterThan{0}, 0, false)
+	expectSatisfied(t, GreaterThan{0}, 0.0, false)
+	expectSatisfied(t, GreaterThan{0}, 0.001, true)
+	expectSatisfied(t, GreaterThan{0}, 1, true)
+	expectSatisfied(t, GreaterThan{0}, -1, false)
+	expectSatisfied(t, GreaterThan{1.5}, uint8(2), true)
+	expectSatisfied(t, GreaterThan{0}, "x", false)
+
+	expectSatisfied(t, LessThan{10}, 10, false)
+	expectSatisfied(t, LessThan{10}, 9.999, true)
+	expectSatisfied(t, LessThan{10}, int64(-5), true)
+	expectSatisfied(t, LessThan{10}, 11, false)
+
+	eq(t, "GreaterThan message", GreaterThan{0}.DefaultMessage(), "Must be greater than 0")
+	eq(t, "LessThan message", LessThan{2.5}.DefaultMessage(), "Must be less than 2.5")
+}
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
+func TestMatchTypes(t *testing.T) {
+	digits := Match{regexp.MustCompile(`^\d+$`)}
+	expectSatisfied(t, digits, "123", true)
+	expectSatisfied(t, digits, []byte("123"), true)
+	expectSatisfied(t, digits, []byte("abc"), false)
+	expectSatisfied(t, digits, stringer("123"), true)
+	expectSatisfied(t, digits, stringer("abc"), false)
+	expectSatisfied(t, digits, 123, false)
+	expectSatisfied(t, digits, nil, false)
+}
+
+func TestDateRange(t *testing.T) {
+	var (
+		min    = time.Date(2012, time.January, 1, 0, 0, 0, 0, time.UTC)
+		max    = time.Date(2012, time.December, 31, 0, 0, 0, 0, time.UTC)
+		inside = time.Date(2012, time.June, 15, 0, 0, 0, 0, time.UTC)
+		before = min.Add(-time.Second)
+		after  = max.Add(time.Second)
+		zero   time.Time
+	)
+
+	bounded := DateRange{min, max}
+	expectSatisfied(t, bounded, inside, true)
+	expectSatisfied(t, bounded, min, true)
+	expectSatisfied(t, bounded, max, true)
+	expectSatisfied(t, bounded, before, false)
+	expectSatisfied(t, bounded, after, false)
+	expectSatisfied(t, bounded, "2012-06-15", false)
+
+	expectSatisfied(t, DateRange{Min: min}, after, true)
+	expectSatisfied(t, DateRange{Min: min}, before, false)
+	expectSatisfied(t, DateRange{Max: max}, before, true)
+	expectSatisfied(t, DateRange{Max: max}, after, false)
+	expectSatisfied(t, DateRange{}, zero, true)
+
+	eq(t, "DateRange message", bounded.DefaultMessage(), "Must be between 2012-01-01 00:00 and 2012-12-31 00:00")
+}
+
+func TestBeforeAfter(t *testing.T) {
+	var (
+		ref     = time.Date(2012, time.June, 15, 12, 0, 0, 0, time.UTC)
+		earlier = ref.Add(-time.Minute)
+		later   = ref.Add(time.Minute)
+		zero    time.Time
+	)
+
+	expectSatisfied(t, Before{ref}, earlier, true)
+	expectSatisfied(t, Before{ref}, later, false)
+	expectSatisfied(t, Before{ref}, ref, false)
+	expectSatisfied(t, Before{ref}, zero, false)
+	expectSatisfied(t, Before{ref}, "2012-06-14", false)
+
+	expectSatisfied(t, After{ref}, later, true)
+	expectSatisfied(t, After{ref}, earlier, false)
+	expectSatisfied(t, After{ref}, ref, false)
+	expectSatisfied(t, After{ref}, zero, false)
+
+	eq(t, "Before message", Before{ref}.DefaultMessage(), "Must be before 2012-06-15 12:00")
+	eq(t, "After message", After{ref}.DefaultMessage(), "Must be after 2012-06-15 12:00")
+
+	v := &Validation{}
+	v.After(earlier, ref).Key("end")
+	v.Before(earlier, ref).Key("start")
+	eq(t, "After error", v.ErrorMap()["end"] != nil, true)
+	eq(t, "Before error", v.ErrorMap()["start"] == nil, true)
+}
+
+func TestDateFormat(t *testing.T) {
+	date := DateFormat{"2006-01-02"}
+	expectSatisfied(t, date, "2021-02-28", true)
+	expectSatisfied(t, date, "02/28/2021", false)
+	expectSatisfied(t, date, "2021-02-30", false)
+	expectSatisfied(t, date, "2021-02-28 10:00", false)
+	expectSatisfied(t, date, "", false)
+	expectSatisfied(t, DateFormat{"15:04"}, "23:59", true)
+	expectSatisfied(t, DateFormat{"15:04"}, "24:00", false)
+	eq(t, "DateFormat message", date.DefaultMessage(), "Must be a date in the format 2006-01-02")
+}
+
+func TestStringRange(t *testing.T) {
+	bounded := StringRange{"b", "d"}
+	expectSatisfied(t, bounded, "c", true)
+	expectSatisfied(t, bounded, "b", true)
+	expectSatisfied(t, bounded, "d", true)
+	expectSatisfied(t, bounded, "a", false)
+	expectSatisfied(t, bounded, "da", false)
+	expectSatisfied(t, bounded, 'c', false)
+
+	expectSatisfied(t, StringRange{Min: "v1.2"}, "v1.9", true)
+	expectSatisfied(t, StringRange{Min: "v1.2"}, "v1.1", false)
+	expectSatisfied(t, StringRange{Max: "v1.2"}, "v1.1", true)
+	expectSatisfied(t, StringRange{Max: "v1.2"}, "v1.9", false)
+	expectSatisfied(t, StringRange{}, "", true)
+
+	eq(t, "StringRange message", bounded.DefaultMessage(), `Must be between "b" and "d", inclusive`)
+	eq(t, "StringRange min message", StringRange{Min: "b"}.DefaultMessage(), `Must be at least "b"`)
+}
+
+func TestSubstrings(t *testing.T) {
+	expectSatisfied(t, Contains{"-"}, "ab-12", true)
+	expectSatisfied(t, Contains{"-"}, "ab12", false)
+	expectSatisfied(t, Contains{"1"}, 12, false)
+
+	expectSatisfied(t, HasPrefix{"INV-"}, "INV-001", true)
+	expectSatisfied(t, HasPrefix{"INV-"}, "001-INV-", false)
+	expectSatisfied(t, HasPrefix{"1"}, 12, false)
+
+	expectSatisfied(t, HasSuffix{".pdf"}, "report.pdf", true)
+	expectSatisfied(t, HasSuffix{".pdf"}, "report.pdf.exe", false)
+	expectSatisfied(t, HasSuffix{"2"}, 12, false)
+}
+
+func TestCoordinates(t *testing.T) {
+	for _, n := range []interface{}{-90, 90, 0, 45.5, -90.0} {
+		expectSatisfied(t, Latitude{}, n, true)
+	}
+	for _, n := range []interface{}{-90.0001, 90.0001, 91, "45"} {
+		expectSatisfied(t, Latitude{}, n, false)
+	}
+
+	for _, n := range []interface{}{-180, 180, 0, -122.4194, 180.0} {
+		expectSatisfied(t, Longitude{}, n, true)
+	}
+	for _, n := range []interface{}{-180.0001, 180.0001, 181, "-122"} {
+		expectSatisfied(t, Longitude{}, n, false)
+	}
+}
+
+func TestEmail(t *testing.T) {
+	valid := []string{"a@example.com", "a+b@example.com", "first.last@sub.example.co.uk"}
+	for _, str := range valid {
+		expectSatisfied(t, Email{}, str, true)
+	}
+
+	invalid := []string{"", "a b@example.com", "example.com", "a@example", "a@@example.com", "a@.com"}
+	for _, str := range invalid {
+		expectSatisfied(t, Email{}, str, false)
+	}
+}
+
+func TestURL(t *testing.T) {
+	expectSatisfied(t, URL{}, "https://example.com:8443/path?q=1", true)
+	expectSatisfied(t, URL{}, "http://example.com", true)
+	expectSatisfied(t, URL{}, "ftp://example.com", false)
+	expectSatisfied(t, URL{}, "javascript:alert(1)", false)
+	expectSatisfied(t, URL{}, "/foo", false)
+	expectSatisfied(t, URL{}, "http://", false)
+	expectSatisfied(t, URL{}, "http://exa mple.com/%zz", false)
+	expectSatisfied(t, URL{}, "", false)
+
+	ftpOnly := URL{[]string{"ftp"}}
+	expectSatisfied(t, ftpOnly, "ftp://example.com/file", true)
+	expectSatisfied(t, ftpOnly, "https://example.com", false)
+}
+
+func TestSizeCountsRunes(t *testing.T) {
+	// "héllo" is five characters, but six bytes.
+	expectSatisfied(t, MinSize{5}, "héllo", true)
+	expectSatisfied(t, MaxSize{5}, "héllo", true)
+	expectSatisfied(t, MinSize{6}, "héllo", false)
+	expectSatisfied(t, MaxSize{4}, "héllo", false)
+	expectSatisfied(t, MaxSize{1}, []interface{}{1, 2}, false)
+}
+
+func TestSizeKinds(t *testing.T) {
+	var (
+		nilSlice []string
+		nilMap   map[string]int
+	)
+	expectSatisfied(t, MaxSize{2}, []string{"a", "b"}, true)
+	expectSatisfied(t, MaxSize{1}, []string{"a", "b"}, false)
+	expectSatisfied(t, MinSize{2}, [2]int{1, 2}, true)
+	expectSatisfied(t, MinSize{3}, [2]int{1, 2}, false)
+	expectSatisfied(t, MinSize{1}, map[string]int{"a": 1}, true)
+	expectSatisfied(t, MaxSize{0}, map[string]int{"a": 1}, false)
+	expectSatisfied(t, MaxSize{0}, nilSlice, true)
+	expectSatisfied(t, MinSize{1}, nilSlice, false)
+	expectSatisfied(t, MaxSize{0}, nilMap, true)
+	expectSatisfied(t, MinSize{1}, nilMap, false)
+	expectSatisfied(t, MaxSize{10}, 5, false)
+	expectSatisfied(t, MaxSize{10}, nil, false)
+}
+
+func TestLength(t *testing.T) {
+	expectSatisfied(t, Length{2}, "US", true)
+	expectSatisfied(t, Length{2}, "USA", false)
+	expectSatisfied(t, Length{2}, []interface{}{1, 2}, true)
+	expectSatisfied(t, Length{2}, []interface{}{1}, false)
+	expectSatisfied(t, Length{2}, 12, false)
+
+	// "né" is two characters, but three bytes.
+	expectSatisfied(t, Length{2}, "né", true)
+	expectSatisfied(t, Length{3}, "né", false)
+}
+
+func TestMinWords(t *testing.T) {
+	expectSatisfied(t, MinWords{3}, "a quick fox", true)
+	expectSatisfied(t, MinWords{3}, "quick fox", false)
+	expectSatisfied(t, MinWords{3}, "   quick   fox   ", false)
+	expectSatisfied(t, MinWords{3}, " a\tquick\n fox ", true)
+	expectSatisfied(t, MinWords{1}, "", false)
+	expectSatisfied(t, MinWords{0}, "", true)
+	expectSatisfied(t, MinWords{1}, []string{"word"}, false)
+	eq(t, "MinWords message", MinWords{3}.DefaultMessage(), "Must contain at least 3 words")
+}
+
+func TestSize(t *testing.T) {
+	expectSatisfied(t, Size{2, 4}, "a", false)
+	expectSatisfied(t, Size{2, 4}, "ab", true)
+	expectSatisfied(t, Size{2, 4}, "abcd", true)
+	expectSatisfied(t, Size{2, 4}, "abcde", false)
+	expectSatisfied(t, Size{2, 4}, "héllo", false)
+	expectSatisfied(t, Size{2, 4}, []int{1, 2, 3}, true)
+	expectSatisfied(t, Size{2, 4}, map[string]int{"a": 1}, false)
+	expectSatisfied(t, Size{2, -1}, strings.Repeat("a", 1000), true)
+	expectSatisfied(t, Size{2, -1}, "a", false)
+	expectSatisfied(t, Size{0, 4}, 12, false)
+
+	v := &Validation{}
+	v.Size("a", 2, 4).Key("name")
+	eq(t, "one error", len(v.Errors), 1)
+	eq(t, "Size message", v.Errors[0].Message, "Length must be between 2 and 4")
+	eq(t, "Size unbounded message", Size{2, -1}.DefaultMessage(), "Length must be at least 2")
+}
+
+func TestByteSize(t *testing.T) {
+	// "héllo" is five characters, but six bytes.
+	expectSatisfied(t, MaxSize{5}, "héllo", true)
+	expectSatisfied(t, ByteSize{0, 5}, "héllo", false)
+	expectSatisfied(t, ByteSize{0, 6}, "héllo", true)
+	expectSatisfied(t, ByteSize{6, 6}, "héllo", true)
+	expectSatisfied(t, ByteSize{7, 0}, "héllo", false)
+	expectSatisfied(t, ByteSize{1, 0}, strings.Repeat("x", 10000), true)
+	expectSatisfied(t, ByteSize{1, 0}, "", false)
+	expectSatisfied(t, ByteSize{0, 2}, []byte("ab"), true)
+	expectSatisfied(t, ByteSize{0, 2}, 12, false)
+
+	eq(t, "ByteSize message", ByteSize{1, 255}.DefaultMessage(), "Must be between 1 and 255 bytes")
+	eq(t, "ByteSize min message", ByteSize{Min: 1}.DefaultMessage(), "Must be at least 1 bytes")
+}
+
+func TestHostname(t *testing.T) {
+	expectSatisfied(t, Hostname{}, "sub.example.com", true)
+	expectSatisfied(t, Hostname{}, "localhost", true)
+	expectSatisfied(t, Hostname{}, "a-b.c0m", true)
+	expectSatisfied(t, Hostname{}, "-bad.com", false)
+	expectSatisfied(t, Hostname{}, "bad-.com", false)
+	expectSatisfied(t, Hostname{}, "under_score.com", false)
+	expectSatisfied(t, Hostname{}, "double..dot", false)
+	expectSatisfied(t, Hostname{}, "http://example.com", false)
+	expectSatisfied(t, Hostname{}, "example.com:80", false)
+	expectSatisfied(t, Hostname{}, "", false)
+	expectSatisfied(t, Hostname{}, strings.Repeat("a", 64)+".com", false)
+	expectSatisfied(t, Hostname{}, strings.Repeat(strings.Repeat("a", 59)+".", 5)+"com", false)
+}
+
+func TestIPAddr(t *testing.T) {
+	expectSatisfied(t, IPAddr{}, "192.168.0.1", true)
+	expectSatisfied(t, IPAddr{}, "2001:db8::1", true)
+	expectSatisfied(t, IPAddr{[]int{IPv6}}, "2001:db8::1", true)
+	expectSatisfied(t, IPAddr{[]int{IPv6}}, "192.168.0.1", false)
+	expectSatisfied(t, IPAddr{[]int{IPv4}}, "2001:db8::1", false)
+	expectSatisfied(t, IPAddr{[]int{IPv4}}, "::ffff:192.168.0.1", false)
+	expectSatisfied(t, IPAddr{}, "256.1.1.1", false)
+	expectSatisfied(t, IPAddr{}, "garbage", false)
+	expectSatisfied(t, IPAddr{}, "", false)
+
+	eq(t, "IPAddr message", IPAddr{}.DefaultMessage(), "Must be a valid IPv4 or IPv6 address")
+	eq(t, "IPAddr v6 message", IPAddr{[]int{IPv6}}.DefaultMessage(), "Must be a valid IPv6 address")
+}
+
+func TestMatchPattern(t *testing.T) {
+	first := MatchPattern(`^\d+$`).(Match)
+	second := MatchPattern(`^\d+$`).(Match)
+	if first.Regexp != second.Regexp {
+		t.Error("Expected the compiled pattern to be cached")
+	}
+	expectSatisfied(t, first, "12345", true)
+	expectSatisfied(t, first, "12a45", false)
+
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("Expected a panic for an invalid pattern")
+		} else if msg := fmt.Sprint(err); !strings.Contains(msg, `"(unclosed"`) {
+			t.Errorf("Expected the pattern in the panic message, got %q", msg)
+		}
+	}()
+	MatchPattern("(unclosed")
+}
+
+func TestMatchAny(t *testing.T) {
+	formats := MatchAny{[]*regexp.Regexp{
+		regexp.MustCompile(`^\d{3}-\d{4}$`),
+		regexp.MustCompile(`^\(\d{3}\) \d{3}-\d{4}$`),
+	}}
+	expectSatisfied(t, formats, "555-1234", true)
+	expectSatisfied(t, formats, "(212) 555-1234", true)
+	expectSatisfied(t, formats, "212.555.1234", false)
+	expectSatisfied(t, formats, 5551234, false)
+	expectSatisfied(t, MatchAny{}, "555-1234", false)
+	eq(t, "MatchAny message", formats.DefaultMessage(), "Invalid format")
+}
+
+func TestMAC(t *testing.T) {
+	expectSatisfied(t, MAC{}, "01:23:45:67:89:ab", true)
+	expectSatisfied(t, MAC{}, "01-23-45-67-89-AB", true)
+	expectSatisfied(t, MAC{}, "0123.4567.89ab", true)
+	expectSatisfied(t, MAC{}, "01:23:45:67:89:ab:cd:ef", true)
+	expectSatisfied(t, MAC{}, "01:23:45:67:89", false)
+	expectSatisfied(t, MAC{}, "not-a-mac", false)
+	expectSatisfied(t, MAC{}, "", false)
+	expectSatisfied(t, MAC{},
+		"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", false)
+}
+
+func TestNumericAndAlpha(t *testing.T) {
+	expectSatisfied(t, Numeric{}, "12345", true)
+	expectSatisfied(t, Numeric{}, "12a45", false)
+	expectSatisfied(t, Numeric{}, "-5", false)
+	expectSatisfied(t, Numeric{}, "", false)
+	expectSatisfied(t, Numeric{}, 12345, false)
+
+	expectSatisfied(t, Alpha{}, "abcXYZ", true)
+	expectSatisfied(t, Alpha{}, "abc1", false)
+	expectSatisfied(t, Alpha{}, "", false)
+
+	expectSatisfied(t, AlphaNumeric{}, "abc123", true)
+	expectSatisfied(t, AlphaNumeric{}, "abc 123", false)
+	expectSatisfied(t, AlphaNumeric{}, "", false)
+}
+
+func TestSlug(t *testing.T) {
+	expectSatisfied(t, Slug{}, "my-post-123", true)
+	expectSatisfied(t, Slug{}, "post", true)
+	expectSatisfied(t, Slug{}, "My_Post", false)
+	expectSatisfied(t, Slug{}, "-bad-", false)
+	expectSatisfied(t, Slug{}, "double--hyphen", false)
+	expectSatisfied(t, Slug{}, "", false)
+}
+
+func TestCase(t *testing.T) {
+	expectSatisfied(t, LowerCase{}, "abc123", true)
+	expectSatisfied(t, LowerCase{}, "my-post_1", true)
+	expectSatisfied(t, LowerCase{}, "Abc", false)
+	expectSatisfied(t, LowerCase{}, "é", true)
+	expectSatisfied(t, LowerCase{}, "É", false)
+	expectSatisfied(t, LowerCase{}, "", true)
+	expectSatisfied(t, LowerCase{}, 123, false)
+
+	expectSatisfied(t, UpperCase{}, "US-123", true)
+	expectSatisfied(t, UpperCase{}, "Usa", false)
+	expectSatisfied(t, UpperCase{}, "", true)
+}
+
+func TestNoHTML(t *testing.T) {
+	expectSatisfied(t, NoHTML{}, "hello", true)
+	expectSatisfied(t, NoHTML{}, "a < b && b > c", true)
+	expectSatisfied(t, NoHTML{}, "Fish & Chips; £5", true)
+	expectSatisfied(t, NoHTML{}, "", true)
+	expectSatisfied(t, NoHTML{}, "<b>hi</b>", false)
+	expectSatisfied(t, NoHTML{}, "<script>alert(1)</script>", false)
+	expectSatisfied(t, NoHTML{}, "x</p>", false)
+	expectSatisfied(t, NoHTML{}, "<!-- comment -->", false)
+	expectSatisfied(t, NoHTML{}, "&lt;script&gt;", false)
+	expectSatisfied(t, NoHTML{}, "&#60;", false)
+	expectSatisfied(t, NoHTML{}, "&#x3C;", false)
+	expectSatisfied(t, NoHTML{}, 5, false)
+}
+
+func TestHexadecimal(t *testing.T) {
+	expectSatisfied(t, Hexadecimal{}, "deadBEEF01", true)
+	expectSatisfied(t, Hexadecimal{}, "abc", true)
+	expectSatisfied(t, Hexadecimal{true}, "abc", false)
+	expectSatisfied(t, Hexadecimal{true}, "abcd", true)
+	expectSatisfied(t, Hexadecimal{}, "0xabcd", false)
+	expectSatisfied(t, Hexadecimal{}, "xyz", false)
+	expectSatisfied(t, Hexadecimal{}, "", false)
+
+	v := &Validation{}
+	v.Hexadecimal("abc").Key("digits")
+	v.HexadecimalBytes("abc").Key("odd")
+	v.HexadecimalBytes("abcd").Key("even")
+	eq(t, "Hexadecimal", v.HasError("digits"), false)
+	eq(t, "HexadecimalBytes (odd)", v.HasError("odd"), true)
+	eq(t, "HexadecimalBytes (even)", v.HasError("even"), false)
+	eq(t, "HexadecimalBytes message", v.Error("odd").Message,
+		"Must be a hexadecimal string with an even number of digits")
+}
+
+func TestBase64(t *testing.T) {
+	// "\xfb\xff" is "+/8=" in the standard encoding, and "-_8" in the URL-safe one.
+	expectSatisfied(t, Base64{}, "aGVsbG8=", true)
+	expectSatisfied(t, Base64{}, "+/8=", true)
+	expectSatisfied(t, Base64{}, "-_8", false)
+	expectSatisfied(t, Base64{}, "aGVsbG8", false)
+	expectSatisfied(t, Base64{true}, "-_8", true)
+	expectSatisfied(t, Base64{true}, "+/8=", false)
+	expectSatisfied(t, Base64{}, "not base64!", false)
+	expectSatisfied(t, Base64{}, "", false)
+}
+
+func TestISBN(t *testing.T) {
+	expectSatisfied(t, ISBN{10}, "0-306-40615-2", true)
+	expectSatisfied(t, ISBN{10}, "0 8044 2957 X", true)
+	expectSatisfied(t, ISBN{10}, "0-306-40615-3", false)
+	expectSatisfied(t, ISBN{10}, "X-306-40615-2", false)
+	expectSatisfied(t, ISBN{10}, "978-0-306-40615-7", false)
+
+	expectSatisfied(t, ISBN{13}, "978-0-306-40615-7", true)
+	expectSatisfied(t, ISBN{13}, "978-0-306-40615-8", false)
+	expectSatisfied(t, ISBN{13}, "0-306-40615-2", false)
+
+	expectSatisfied(t, ISBN{}, "0306406152", true)
+	expectSatisfied(t, ISBN{}, "9780306406157", true)
+	expectSatisfied(t, ISBN{}, "978030640615", false)
+	expectSatisfied(t, ISBN{}, 9780306406157, false)
+}
+
+func TestJSON(t *testing.T) {
+	expectSatisfied(t, JSON{}, `{"tags": ["a", "b"], "count": 2}`, true)
+	expectSatisfied(t, JSON{}, `[1, 2, 3]`, true)
+	expectSatisfied(t, JSON{}, `"string"`, true)
+	expectSatisfied(t, JSON{}, `{"unclosed": [1, 2}`, false)
+	expectSatisfied(t, JSON{}, `{'single': 1}`, false)
+	expectSatisfied(t, JSON{}, "", false)
+	expectSatisfied(t, JSON{}, []byte("{}"), false)
+}
+
+func TestCreditCard(t *testing.T) {
+	expectSatisfied(t, CreditCard{}, "4111 1111 1111 1111", true)
+	expectSatisfied(t, CreditCard{}, "4111-1111-1111-1111", true)
+	expectSatisfied(t, CreditCard{}, "378282246310005", true)
+	expectSatisfied(t, CreditCard{}, "4111 1111 1111 1112", false)
+	expectSatisfied(t, CreditCard{}, "4111 1111 1111 111a", false)
+	expectSatisfied(t, CreditCard{}, "4111", false)
+	expectSatisfied(t, CreditCard{}, "", false)
+}
+
+func TestPhone(t *testing.T) {
+	us := Phone{"US"}
+	for _, str := range []string{"(415) 555-0132", "415-555-0132", "415.555.0132",
+		"4155550132", "1 415 555 0132", "+1 (415) 555-0132", "+14155550132"} {
+		expectSatisfied(t, us, str, true)
+	}
+	expectSatisfied(t, us, "555-0132", false)
+	expectSatisfied(t, us, "(015) 555-0132", false)
+	expectSatisfied(t, us, "(415-555-0132", false)
+	expectSatisfied(t, us, "415)555-0132", false)
+	expectSatisfied(t, us, "+1 (415 555-0132", false)
+
+	// Unknown regions only accept E.164.
+	expectSatisfied(t, Phone{"FR"}, "+33142685300", true)
+	expectSatisfied(t, Phone{"FR"}, "01 42 68 53 00", false)
+	expectSatisfied(t, Phone{}, "+442079460958", true)
+	expectSatisfied(t, Phone{}, "call me maybe", false)
+	expectSatisfied(t, Phone{"US"}, "", false)
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		str, region, expected string
+		ok                    bool
+	}{
+		{"(415) 555-0132", "US", "+14155550132", true},
+		{"415.555.0132", "us", "+14155550132", true},
+		{"+1 415-555-0132", "CA", "+14155550132", true},
+		{" +442071838750 ", "GB", "+442071838750", true},
+		{"(415) 555-0132", "GB", "", false},
+		{"555-0132", "US", "", false},
+		{"not a number", "US", "", false},
+	}
+	for _, test := range tests {
+		actual, ok := NormalizePhone(test.str, test.region)
+		eq(t, test.str+" in "+test.region, actual, test.expected)
+		eq(t, test.str+" in "+test.region+" (ok)", ok, test.ok)
+		expectSatisfied(t, Phone{test.region}, test.str, test.ok)
+	}
+}
+
+func TestPostalCode(t *testing.T) {
+	expectSatisfied(t, PostalCode{"US"}, "94107", true)
+	expectSatisfied(t, PostalCode{"us"}, "94107-1234", true)
+	expectSatisfied(t, PostalCode{"US"}, "9410", false)
+	expectSatisfied(t, PostalCode{"US"}, "94107-12", false)
+	expectSatisfied(t, PostalCode{"US"}, "K1A 0B1", false)
+
+	expectSatisfied(t, PostalCode{"CA"}, "K1A 0B1", true)
+	expectSatisfied(t, PostalCode{"CA"}, "k1a0b1", true)
+	expectSatisfied(t, PostalCode{"CA"}, "D1A 0B1", false)
+
+	expectSatisfied(t, PostalCode{"GB"}, "SW1A 1AA", true)
+	expectSatisfied(t, PostalCode{"UK"}, "M1 1AE", true)
+	expectSatisfied(t, PostalCode{"GB"}, "94107", false)
+
+	expectSatisfied(t, PostalCode{"FR"}, "75008", true)
+	expectSatisfied(t, PostalCode{""}, "  ", false)
+	expectSatisfied(t, PostalCode{"US"}, 94107, false)
+}
+
+func TestPassword(t *testing.T) {
+	all := Password{MinLen: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSpecial: true}
+	tests := []struct {
+		password, message string
+	}{
+		{"Ab1!", "Must be at least 8 characters"},
+		{"abcdefg1!", "Must contain at least one uppercase letter"},
+		{"ABCDEFG1!", "Must contain at least one lowercase letter"},
+		{"Abcdefgh!", "Must contain at least one digit"},
+		{"Abcdefg12", "Must contain at least one special character"},
+		{"Abcdefg1!", ""},
+	}
+
+	for _, test := range tests {
+		v := &Validation{}
+		result := v.Password(test.password, all)
+		if test.message == "" {
+			expectSatisfied(t, all, test.password, true)
+			if v.HasErrors() {
+				t.Errorf("%s: unexpected errors %v", test.password, v.Errors)
+			}
+			continue
+		}
+		expectSatisfied(t, all, test.password, false)
+		if result.Ok {
+			t.Errorf("%s: expected an error", test.password)
+			continue
+		}
+		eq(t, test.password, result.Error.Message, test.message)
+	}
+
+	// The default policy does not require special characters.
+	v := &Validation{}
+	if !v.Password("Abcdefg1").Ok || v.Password("abcdefg1").Ok {
+		t.Errorf("Unexpected results for DefaultPasswordOptions: %v", v.Errors)
+	}
+
+	// The default message describes only the enabled rules.
+	eq(t, "all message", all.DefaultMessage(),
+		"Must be at least 8 characters and contain an uppercase letter, a lowercase letter, a digit, and a special character")
+	eq(t, "default message", DefaultPasswordOptions.DefaultMessage(),
+		"Must be at least 8 characters and contain an uppercase letter, a lowercase letter, and a digit")
+	eq(t, "length message", Password{MinLen: 12}.DefaultMessage(), "Must be at least 12 characters")
+	eq(t, "kinds message", Password{RequireDigit: true, RequireSpecial: true}.DefaultMessage(),
+		"Must contain a digit and a special character")
+}
+
+func TestCurrency(t *testing.T) {
+	c := DefaultCurrencyOptions
+	expectSatisfied(t, c, "10.00", true)
+	expectSatisfied(t, c, "10.5", true)
+	expectSatisfied(t, c, "10", true)
+	expectSatisfied(t, c, "10.001", false)
+	expectSatisfied(t, c, "-5.00", false)
+	expectSatisfied(t, c, "abc", false)
+	expectSatisfied(t, c, "10.", false)
+	expectSatisfied(t, c, "", false)
+	expectSatisfied(t, c, 10.0, false)
+
+	expectSatisfied(t, Currency{MaxDecimals: 2, AllowNegative: true}, "-5.00", true)
+	expectSatisfied(t, Currency{MaxDecimals: -1}, "500", true)
+	expectSatisfied(t, Currency{MaxDecimals: -1}, "500.0", false)
+	eq(t, "whole Currency message", Currency{MaxDecimals: -1}.DefaultMessage(), "Must be a non-negative whole amount")
+
+	// The zero value defaults to 2 decimal places.
+	expectSatisfied(t, Currency{AllowNegative: true}, "10.50", true)
+	expectSatisfied(t, Currency{AllowNegative: true}, "10.505", false)
+	eq(t, "zero Currency message", Currency{}.DefaultMessage(), c.DefaultMessage())
+	eq(t, "Currency message", c.DefaultMessage(), "Must be a non-negative amount with at most 2 decimal places")
+}
+
+func TestIn(t *testing.T) {
+	statuses := In{[]interface{}{"draft", "published", "archived"}}
+	expectSatisfied(t, statuses, "published", true)
+	expectSatisfied(t, statuses, "deleted", false)
+	expectSatisfied(t, In{[]interface{}{1, 2, 3}}, 2, true)
+	expectSatisfied(t, In{[]interface{}{1, 2, 3}}, 4, false)
+	expectSatisfied(t, In{}, "", false)
+	eq(t, "In message", statuses.DefaultMessage(), "Must be one of: draft, published, archived")
+}
+
+type color int
+
+const (
+	red color = iota + 1
+	green
+	blue
+)
+
+func TestEnum(t *testing.T) {
+	enum := EnumInt{[]int{1, 2, 3}}
+	expectSatisfied(t, enum, 2, true)
+	expectSatisfied(t, enum, 4, false)
+	expectSatisfied(t, enum, 0, false)
+	expectSatisfied(t, enum, int64(2), false)
+	expectSatisfied(t, enum, "2", false)
+	expectSatisfied(t, EnumInt{}, 0, false)
+	eq(t, "EnumInt message", enum.DefaultMessage(), "Must be one of: 1, 2, 3")
+
+	v := &Validation{}
+	v.Enum(green, red, green, blue).Key("color")
+	v.Enum("small", "small", "large").Key("size")
+	eq(t, "valid enums", v.HasErrors(), false)
+
+	v.Enum(color(4), red, green, blue).Key("color")
+	v.Enum(2, red, green, blue).Key("untyped")
+	v.Enum("medium", "small", "large").Key("size")
+	v.EnumInt(5, 1, 2, 3).Key("int")
+	eq(t, "invalid enums", v.Len(), 4)
+}
+
+func TestNotIn(t *testing.T) {
+	reserved := NotIn{[]interface{}{"admin", "root", "system"}}
+	expectSatisfied(t, reserved, "root", false)
+	expectSatisfied(t, reserved, "rob", true)
+	expectSatisfied(t, reserved, "Root", true)
+	expectSatisfied(t, NotIn{[]interface{}{0}}, 0, false)
+	expectSatisfied(t, NotIn{[]interface{}{0}}, int64(0), true)
+	expectSatisfied(t, NotIn{}, "", true)
+
+	v := &Validation{Normalizer: strings.ToLower}
+	v.NotIn("Root", "admin", "root", "system").Key("username")
+	eq(t, "NotIn (normalized)", v.Error("username").Message, "This value is reserved")
+}
+
+func TestIntIn(t *testing.T) {
+	sizes := NewIntIn(8, 10, 12)
+	expectSatisfied(t, sizes, 10, true)
+	expectSatisfied(t, sizes, 9, false)
+	expectSatisfied(t, sizes, "10", false)
+	expectSatisfied(t, sizes, int64(10), false)
+	expectSatisfied(t, IntIn{}, 0, false)
+	expectSatisfied(t, NewIntIn(), 0, false)
+	expectSatisfied(t, IntIn{Values: []int{8, 10}}, 10, true)
+	expectSatisfied(t, IntIn{Values: []int{8, 10}}, 9, false)
+	eq(t, "IntIn message", sizes.DefaultMessage(), "Must be one of: 8, 10, 12")
+
+	v := &Validation{}
+	v.IntIn(3, 1, 2, 3).Key("ok")
+	v.IntIn(4, 1, 2, 3).Key("bad")
+	eq(t, "IntIn ok", v.HasError("ok"), false)
+	eq(t, "IntIn bad", v.HasError("bad"), true)
+}
+
+func TestEach(t *testing.T) {
+	emails := []string{"rob@example.com", "rob", "bill@example.com", "bill"}
+	expectSatisfied(t, Each{Email{}}, emails, false)
+	expectSatisfied(t, Each{Email{}}, emails[:1], true)
+	expectSatisfied(t, Each{Email{}}, []string{}, true)
+	expectSatisfied(t, Each{Email{}}, "rob@example.com", false)
+
+	v := &Validation{}
+	result := v.Each(emails, Email{}).Key("emails")
+	if result.Ok || len(v.Errors) != 2 {
+		t.Fatalf("Expected two errors, got %v", v)
+	}
+	eq(t, "first key", v.Errors[0].Key, "emails[1]")
+	eq(t, "second key", v.Errors[1].Key, "emails[3]")
+	eq(t, "result error", result.Error, v.Errors[0])
+	eq(t, "message", v.Errors[1].Message, Email{}.DefaultMessage())
+
+	v.Clear()
+	v.Each(emails, Email{}).Message("Bad email")
+	eq(t, "unkeyed", v.Errors[1].Key, "")
+	eq(t, "all messages", v.Errors[1].Message, "Bad email")
+
+	v = &Validation{StopOnFirst: true}
+	v.Each(emails, Email{}).Key("emails")
+	eq(t, "StopOnFirst errors", v.String(), "emails[1]: "+Email{}.DefaultMessage())
+	if v.Each(emails, Email{}).Key("more").Ok || v.HasError("more[1]") {
+		t.Errorf("Expected Each to stop after the first error, got %v", v.Errors)
+	}
+
+	v = &Validation{}
+	if !v.Each(emails[:1], Email{}).Key("emails").Ok || v.Each("x", Email{}).Ok {
+		t.Error("Unexpected result for Each")
+	}
+}
+
+func TestUnique(t *testing.T) {
+	expectSatisfied(t, Unique{}, []string{"a", "a"}, false)
+	expectSatisfied(t, Unique{}, []int{1, 2, 3}, true)
+	expectSatisfied(t, Unique{}, [3]int{1, 2, 1}, false)
+	expectSatisfied(t, Unique{}, []string{}, true)
+	expectSatisfied(t, Unique{}, []string{"a"}, true)
+	expectSatisfied(t, Unique{}, []interface{}{[]int{1}, []int{1}}, false)
+	expectSatisfied(t, Unique{}, []interface{}{[]int{1}, []int{2}}, true)
+	expectSatisfied(t, Unique{}, "aa", false)
+}
+
+func TestNot(t *testing.T) {
+	reserved := Not{In{[]interface{}{"admin", "root"}}}
+	expectSatisfied(t, reserved, "admin", false)
+	expectSatisfied(t, reserved, "rob", true)
+	eq(t, "Not message", reserved.DefaultMessage(), "Must not: Must be one of: admin, root")
+
+	empty := Not{Required{}}
+	expectSatisfied(t, empty, "", true)
+	expectSatisfied(t, empty, "x", false)
+
+	v := &Validation{}
+	v.Check("admin", Required{}, reserved).Key("username")
+	if !v.HasError("username") {
+		t.Errorf("Expected an error for username")
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	v := &Validation{}
+	v.Confirmation("secret", "secret").Key("password_confirmation")
+	v.Confirmation("", "").Key("password_confirmation")
+	if v.HasErrors() {
+		t.Errorf("Validation has errors!\n%v\n", v.ErrorMap())
+	}
+
+	v.Confirmation("secret", "secrte").Key("password_confirmation")
+	err, ok := v.ErrorMap()["password_confirmation"]
+	if !ok {
+		t.Fatalf("Expected an error for password_confirmation, got %v", v.ErrorMap())
+	}
+	eq(t, "Confirmation message", err.Message, "Does not match")
+	eq(t, "Confirmation code", err.Code, "confirmation")
+
+	// The message may be overridden, like any other.
+	v = &Validation{Messages: map[string]string{"Confirmation": "Passwords differ"}}
+	v.Confirmation("secret", "secrte").Key("password_confirmation")
+	eq(t, "Confirmation override", v.Error("password_confirmation").Message, "Passwords differ")
+
+	expectSatisfied(t, Equals{[]int{1, 2}}, []int{1, 2}, true)
+	expectSatisfied(t, Equals{1}, int64(1), false)
+}
+
+func TestSecureEquals(t *testing.T) {
+	token := SecureEquals{"s3cr3t-t0k3n"}
+	expectSatisfied(t, token, "s3cr3t-t0k3n", true)
+	expectSatisfied(t, token, "s3cr3t-t0k3m", false)
+	expectSatisfied(t, token, "s3cr3t", false)
+	expectSatisfied(t, token, "s3cr3t-t0k3n-and-more", false)
+	expectSatisfied(t, token, "", false)
+	expectSatisfied(t, token, []byte("s3cr3t-t0k3n"), false)
+	expectSatisfied(t, SecureEquals{}, "", false)
+	eq(t, "SecureEquals message", token.DefaultMessage(), "Invalid value")
+}
+
+type structTest struct {
+	Name     string `valid:"required,minSize=3,maxSize=20"`
+	Age      int    `valid:"min=13,max=120" json:"age"`
+	Code     string `valid:"required,match=^[a-z]{2,4}$"`
+	Optional string
+	private  string `valid:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	v := &Validation{}
+	v.Struct(structTest{Name: "rob", Age: 30, Code: "abc"})
+	if v.HasErrors() {
+		t.Errorf("Validation has errors!\n%v\n", v.ErrorMap())
+	}
+
+	v = &Validation{}
+	v.Struct(&structTest{Name: "ro", Age: 5, Code: "toolong"})
+	errorMap := v.ErrorMap()
+	for _, key := range []string{"Name", "age", "Code"} {
+		if _, ok := errorMap[key]; !ok {
+			t.Errorf("Expected an error for %s, got %v", key, errorMap)
+		}
+	}
+	if len(errorMap) != 3 {
+		t.Errorf("Expected 3 errors, got %v", errorMap)
+	}
+}
+
+func TestStopOnFirst(t *testing.T) {
+	invalid := structTest{Name: "ro", Age: 5, Code: "abc"}
+
+	v := &Validation{}
+	v.Struct(invalid)
+	v.Check("", Required{}).Key("other")
+	if len(v.Errors) != 3 {
+		t.Errorf("Expected 3 errors, got %v", v.ErrorMap())
+	}
+
+	v = &Validation{StopOnFirst: true}
+	v.Struct(invalid)
+	result := v.Check("", Required{}).Key("other")
+	if len(v.Errors) != 1 || !v.HasError("Name") {
+		t.Errorf("Expected only the Name error, got %v", v.ErrorMap())
+	}
+	if result.Ok || result.Error != nil {
+		t.Errorf("Expected a skipped result, got %v", result)
+	}
+
+	// The convenience methods are skipped too.
+	result = v.Required("").Key("other")
+	if len(v.Errors) != 1 || result.Ok || result.Error != nil {
+		t.Errorf("Expected Required to be skipped, got %v", v.ErrorMap())
+	}
+	if v.Min(1, 5).Ok {
+		t.Error("Expected a skipped Min to not be Ok")
+	}
+}
+
+type groupTest struct {
+	Username string `valid:"required"`
+	Password string `valid:"required" groups:"create"`
+	Reason   string `valid:"required" groups:"update,delete"`
+}
+
+func TestStructGroup(t *testing.T) {
+	tests := map[string][]string{
+		"create": {"Username", "Password"},
+		"update": {"Username", "Reason"},
+		"":       {"Username", "Password", "Reason"},
+	}
+	for group, expected := range tests {
+		v := &Validation{}
+		v.StructGroup(groupTest{}, group)
+		actual := []string{}
+		for _, err := range v.Errors {
+			actual = append(actual, err.Key)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Group %q: (expected) %v != %v (actual)", group, expected, actual)
+		}
+	}
+}
+
+type lineItem struct {
+	Product  string `valid:"required" json:"product"`
+	Quantity int    `valid:"min=1" json:"quantity"`
+}
+
+type customer struct {
+	Name string `valid:"required" json:"name"`
+}
+
+type order struct {
+	Customer customer    `json:"customer"`
+	Billing  *customer   `json:"billing"`
+	Items    []lineItem  `json:"items"`
+	Extras   []*lineItem `json:"extras"`
+}
+
+func TestStructNested(t *testing.T) {
+	v := &Validation{}
+	v.Struct(order{
+		Items:  []lineItem{{"apple", 1}, {"pear", 0}},
+		Extras: []*lineItem{nil, {"", 2}},
+	})
+
+	expected := []string{"customer.name", "items[1].quantity", "extras[1].product"}
+	actual := []string{}
+	for _, err := range v.Errors {
+		actual = append(actual, err.Key)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("(expected) %v != %v (actual)", expected, actual)
+	}
+}
+
+type node struct {
+	Name string `valid:"required"`
+	Next *node
+}
+
+func TestStructCycle(t *testing.T) {
+	n := &node{}
+	n.Next = &node{Name: "b", Next: n}
+
+	v := &Validation{}
+	v.Struct(n)
+	if len(v.Errors) != 1 || v.Errors[0].Key != "Name" {
+		t.Errorf("Unexpected errors: %v", v.ErrorMap())
+	}
+}
+
+func TestCheckKey(t *testing.T) {
+	v := &Validation{}
+	result := v.CheckKey("username", "", Required{}, MinSize{4}, MaxSize{15})
+	if result.Ok || len(v.Errors) != 1 {
+		t.Fatalf("Expected one error, got %v", v.ErrorMap())
+	}
+	eq(t, "key", v.Errors[0].Key, "username")
+	eq(t, "message", v.Errors[0].Message, Required{}.DefaultMessage())
+
+	v.CheckKey("nickname", "bob", Required{}, MinSize{4}).Message("Too short")
+	eq(t, "second check key", v.Error("nickname").Message, "Too short")
+
+	if !v.CheckKey("name", "Rob", Required{}).Ok || !v.CheckKey("none", "").Ok {
+		t.Error("Expected passing checks to be Ok")
+	}
+	eq(t, "error count", len(v.Errors), 2)
+}
+
+func TestValidate(t *testing.T) {
+	rules := map[string][]Check{
+		"email":    {Required{}, Email{}},
+		"age":      {Min{13}},
+		"name":     {Required{}},
+		"nickname": {MaxSize{10}},
+		"none":     {},
+	}
+	v := &Validation{}
+	v.Validate(rules, map[string]interface{}{
+		"email":    "rob",
+		"age":      12,
+		"nickname": "bobby",
+	})
+
+	expected := map[string]string{
+		"age":   Min{13}.DefaultMessage(),
+		"email": Email{}.DefaultMessage(),
+		"name":  Required{}.DefaultMessage(),
+	}
+	actual := map[string]string{}
+	for key, err := range v.ErrorMap() {
+		actual[key] = err.Message
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("(expected) %v != %v (actual)", expected, actual)
+	}
+	eq(t, "sorted order", v.Errors[0].Key+","+v.Errors[1].Key+","+v.Errors[2].Key, "age,email,name")
+}
+
+func TestField(t *testing.T) {
+	v := &Validation{}
+	age, name := 12, ""
+	v.Field("age").Required(age).Min(age, 18).Max(age, 99)
+	v.Field("name").Required(name).Message("Name, please").MinSize(name, 2)
+	v.Field("email").Email("rob@example.com")
+
+	eq(t, "error count", len(v.Errors), 3)
+	for _, err := range v.Errors {
+		if err.Key != "age" && err.Key != "name" {
+			t.Errorf("Unexpected error key %q", err.Key)
+		}
+	}
+	eq(t, "age error", v.Errors[0].Message, Min{18}.DefaultMessage())
+	eq(t, "name error", v.Errors[1].Message, "Name, please")
+	eq(t, "name size error", v.Errors[2].Message, MinSize{2}.DefaultMessage())
+	eq(t, "email error", v.HasError("email"), false)
+
+	v = &Validation{StopOnFirst: true}
+	v.Field("age").Min(age, 18).Message("Too young").Max(age, 10)
+	eq(t, "StopOnFirst error count", len(v.Errors), 1)
+	eq(t, "StopOnFirst message", v.Errors[0].Message, "Too young")
+}
+
+func TestStructUnknownRule(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Error("Expected a panic for an unknown rule")
+		}
+	}()
+	(&Validation{}).Struct(struct {
+		Name string `valid:"requird"`
+	}{})
+}
+
+func TestStructTagSpaces(t *testing.T) {
+	type spaced struct {
+		Code string `valid:"required, minSize=2, match=^[a-z]{2,4}$"`
+	}
+	v := &Validation{}
+	v.Struct(spaced{"abc"})
+	eq(t, "valid code", v.HasErrors(), false)
+	v.Struct(spaced{"abcdef"})
+	eq(t, "invalid code", v.HasError("Code"), true)
+}
+
+func TestStructBadPattern(t *testing.T) {
+	v := &Validation{}
+	v.Struct(struct {
+		Code string `valid:"required,match=^[a-z"`
+	}{"abc"})
+	if !v.HasErrors() || v.Error("Code") == nil {
+		t.Fatalf("Expected an error for a bad pattern, got %v", v.Errors)
+	}
+	if !strings.Contains(v.Error("Code").Message, "Bad pattern") {
+		t.Errorf("Unexpected message: %q", v.Error("Code").Message)
+	}
+}
+
+func TestStructTagCache(t *testing.T) {
+	tag := `match=^[a-z]+$`
+	first, err := parseValidTag(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := parseValidTag(tag)
+	if first[0].(Match).Regexp != second[0].(Match).Regexp {
+		t.Error("Expected the compiled pattern to be reused")
+	}
+}
+
+func TestDefaultMessages(t *testing.T) {
+	DefaultMessages["Required"] = "Ce champ est obligatoire"
+	DefaultMessages["Range"] = "Must be between {0} and {1}"
+	defer func() {
+		delete(DefaultMessages, "Required")
+		delete(DefaultMessages, "Range")
+	}()
+
+	v := &Validation{}
+	v.Required("").Key("name")
+	v.Range(200, 1, 100).Key("count")
+	v.Min(0, 5).Key("min")
+
+	errorMap := v.ErrorMap()
+	eq(t, "Required message", errorMap["name"].Message, "Ce champ est obligatoire")
+	eq(t, "Range message", errorMap["count"].Message, "Must be between 1 and 100")
+	eq(t, "Min message", errorMap["min"].Message, Min{5}.DefaultMessage())
+}
+
+func TestTranslator(t *testing.T) {
+	v := &Validation{Translator: strings.ToUpper}
+	v.Required("").Key("name")
+	v.Email("rob").Key("email")
+
+	eq(t, "name", v.Error("name").Message, "REQUIRED")
+	eq(t, "email", v.Error("email").Message, "MUST BE A VALID EMAIL ADDRESS")
+}
+
+func TestWithMessage(t *testing.T) {
+	adult := WithMessage(Min{18}, "Must be 18+")
+	for _, age := range []int{17, 18, 30} {
+		eq(t, fmt.Sprint("WithMessage ", age), adult.IsSatisfied(age), Min{18}.IsSatisfied(age))
+	}
+	eq(t, "DefaultMessage", adult.DefaultMessage(), "Must be 18+")
+
+	v := &Validation{}
+	v.Check(17, Required{}, adult).Key("age")
+	eq(t, "message", v.Error("age").Message, "Must be 18+")
+	eq(t, "code", v.Error("age").Code, "min")
+}
+
+func TestValidator(t *testing.T) {
+	DefaultMessages["Required"] = "Ce champ est obligatoire"
+	defer delete(DefaultMessages, "Required")
+
+	validator := Validator{
+		Messages:   map[string]string{"Min": "Au moins {0}", "Required": "Obligatoire"},
+		Translator: strings.ToUpper,
+	}
+
+	for i := 0; i < 2; i++ {
+		v := validator.New()
+		v.Required("").Key("name")
+		v.Min(0, 5).Key("min")
+		v.Email("rob").Key("email")
+
+		eq(t, "Required message", v.Error("name").Message, "OBLIGATOIRE")
+		eq(t, "Min message", v.Error("min").Message, "AU MOINS 5")
+		eq(t, "Email message", v.Error("email").Message, "MUST BE A VALID EMAIL ADDRESS")
+	}
+
+	// Contexts that are not from the Validator are unaffected.
+	v := &Validation{}
+	v.Required("").Key("name")
+	eq(t, "plain Required message", v.Error("name").Message, "Ce champ est obligatoire")
+}
+
+// Registration may happen while other goroutines validate and bind.
+// Run with -race to check.
+func TestConcurrentRegistration(t *testing.T) {
+	var types []reflect.Type
+	for i := 1; i <= 20; i++ {
+		types = append(types, reflect.ArrayOf(i, reflect.TypeOf(byte(0))))
+	}
+	defer func() {
+		for _, typ := range types {
+			delete(TypeBinders, typ)
+		}
+		SetDefaultMessage("Email", "")
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, typ := range types {
+			RegisterBinder(typ, func(values []string) reflect.Value { return reflect.Zero(typ) })
+			SetDefaultMessage("Email", fmt.Sprint("Invalid email ", i))
+			MatchPattern(fmt.Sprintf("^%d$", i))
+		}
+	}()
+
+	params := &Params{Values: map[string][]string{"n": {"5"}}}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if Bind(params, "n", reflect.TypeOf(0)).Int() != 5 {
+					t.Error("Bind failed")
+				}
+				v := &Validation{}
+				v.Email("rob")
+				v.Check("12", MatchPattern(`^\d+$`))
+				if v.Len() != 1 {
+					t.Errorf("Unexpected errors: %v", v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	eq(t, "registered binders", Bind(params, "n", types[19]).Type(), types[19])
+	v := &Validation{}
+	eq(t, "message", v.Email("rob").Error.Message, "Invalid email 19")
+}
+
+func TestValidationPool(t *testing.T) {
+	v := AcquireValidation()
+	v.Required("").Key("name")
+	v.Translator = strings.ToUpper
+	ReleaseValidation(v)
+
+	for i := 0; i < 10; i++ {
+		v = AcquireValidation()
+		if v.HasErrors() || v.keep || v.Translator != nil {
+			t.Fatalf("Acquired a dirty Validation: %#v", v)
+		}
+		ReleaseValidation(v)
+	}
+
+	// Kept contexts are not recycled.
+	v = AcquireValidation()
+	v.Required("").Key("name")
+	v.Keep()
+	ReleaseValidation(v)
+	if !v.HasErrors() || !v.keep {
+		t.Errorf("Released a kept Validation: %#v", v)
+	}
+}
+
+func BenchmarkValidationAlloc(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v := &Validation{}
+		v.Required("").Key("name")
+		v.MinSize("", 3).Key("name")
+	}
+}
+
+func BenchmarkValidationPool(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v := AcquireValidation()
+		v.Required("").Key("name")
+		v.MinSize("", 3).Key("name")
+		ReleaseValidation(v)
+	}
+}
+
+func BenchmarkMatchCompile(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v := &Validation{}
+		v.Check("12345", Match{regexp.MustCompile(`^\d+$`)})
+	}
+}
+
+func BenchmarkMatchPattern(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		v := &Validation{}
+		v.Check("12345", MatchPattern(`^\d+$`))
+	}
+}
+
+var (
+	benchIntIn = NewIntIn(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	benchIn    = In{[]interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+)
+
+func BenchmarkIntIn(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchIntIn.IsSatisfied(10)
+	}
+}
+
+func BenchmarkValidationIntIn(b *testing.B) {
+	v := &Validation{}
+	for i := 0; i < b.N; i++ {
+		v.IntIn(10, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	}
+}
+
+func BenchmarkIn(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchIn.IsSatisfied(10)
+	}
+}
+
+func expectSatisfied(t *testing.T, check Check, obj interface{}, expected bool) {
+	if actual := check.IsSatisfied(obj); actual != expected {
+		t.Errorf("%#v.IsSatisfied(%#v): (expected) %v != %v (actual)",
+			check, obj, expected, actual)
+	}
+}